@@ -1,44 +1,108 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	readline "github.com/chzyer/readline"
 	"github.com/yassirdeveloper/cli/command"
+	clierrors "github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/cli/operator"
 )
 
 const DEFAULT_SYMBOL = ">"
 const DEFAULT_HISTORY_LIMIT = 100
 
-var DEFAULT_OPERATOR = operator.NewStdOperator('\n', 4096)
+const DEFAULT_DELIMITER = '\n'
+const DEFAULT_MAX_READ_SIZE = 4096
+
+const DEFAULT_SHUTDOWN_TIMEOUT = 5 * time.Second
+
+// continueOnErrorFlag keeps running the script piped to the standard input
+// past its failing commands. It is taken along with global options only, as
+// other arguments run a command.
+const continueOnErrorFlag = "--continue-on-error"
 
 type Cli struct {
 	Name         string
 	HistoryLimit int
-	Symbol       string
-	commander    command.Commander
+	// HistoryFile is where the interactive shell history persists across
+	// sessions, defaulting to DefaultHistoryFile. Empty keeps it in memory.
+	HistoryFile string
+	Symbol      string
+	// ShutdownTimeout bounds the time given to the shutdown hooks to run.
+	ShutdownTimeout time.Duration
+	// ContinueOnError keeps running the script piped to the standard input
+	// past its failing commands, as the --continue-on-error flag does.
+	ContinueOnError bool
+	commander       command.Commander
+	prompt          *template.Template
+	promptFunc      func(PromptState) string
 }
 
 func NewCli(name string, version string) (*Cli, error) {
-	commander := command.GetCommander()
-	commander.SetOperator(DEFAULT_OPERATOR)
+	commander := command.NewCommander().SetName(name).SetConfigFiles(command.DefaultConfigFiles(name)...)
+	commander.SetOperator(operator.NewStdOperator(DEFAULT_DELIMITER, DEFAULT_MAX_READ_SIZE))
 	cli := &Cli{
-		commander:    commander,
-		Name:         name,
-		HistoryLimit: DEFAULT_HISTORY_LIMIT,
-		Symbol:       DEFAULT_SYMBOL,
+		commander:       commander,
+		Name:            name,
+		HistoryLimit:    DEFAULT_HISTORY_LIMIT,
+		HistoryFile:     DefaultHistoryFile(name),
+		Symbol:          DEFAULT_SYMBOL,
+		ShutdownTimeout: DEFAULT_SHUTDOWN_TIMEOUT,
 	}
+	cli.prompt = template.Must(parsePrompt(DEFAULT_PROMPT))
 	err := cli.AddCommand(command.ExitCommand())
 	if err != nil {
 		return cli, err
 	}
-	err = cli.AddCommand(command.HelpCommand(""))
+	err = cli.AddCommand(command.HelpCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.HistoryCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.RunCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.SourceCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.CompletionCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.CompleteCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddCommand(command.ConfigCommand())
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddGlobalOption(command.ConfigFileOption)
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddGlobalOption(command.OutputOption)
+	if err != nil {
+		return cli, err
+	}
+	err = cli.AddGlobalOption(command.InteractiveOption)
 	if err != nil {
 		return cli, err
 	}
@@ -50,7 +114,7 @@ func NewCli(name string, version string) (*Cli, error) {
 }
 
 func (cli *Cli) GetVersion() string {
-	return command.GetVersionString()
+	return command.GetVersionString(cli.commander)
 }
 
 func (cli *Cli) SetOperator(operator operator.Operator) *Cli {
@@ -70,83 +134,246 @@ func (cli *Cli) SetVersion(version string) (*Cli, error) {
 		return nil, errors.New("invalid version format. Expected semantic versioning format: X.Y.Z[-pre-release][+build-metadata] (e.g., 1.0.0, v1.2.3-alpha.1)")
 	}
 
-	cli.AddCommand(command.VersionCommand(version))
+	cli.commander.SetVersion(version)
+	if _, exists := cli.commander.Get("version"); !exists {
+		cli.AddCommand(command.VersionCommand())
+	}
 	return cli, nil
 }
 
 func (cli *Cli) SetHelpText(helpText string) *Cli {
-	cli.AddCommand(command.HelpCommand(helpText))
+	cli.commander.SetHelpText(helpText)
+	return cli
+}
+
+// SetSuggestionDistance sets the largest number of edits between an unknown
+// command or flag and the ones suggested in its place, 0 disabling suggestions.
+func (cli *Cli) SetSuggestionDistance(distance int) *Cli {
+	cli.commander.SetSuggestionDistance(distance)
 	return cli
 }
 
-func (cli *Cli) AddCommand(command command.Command) error {
+// AddCommand registers command, which can also be run under any of aliases.
+func (cli *Cli) AddCommand(command command.Command, aliases ...string) error {
 	err := command.Validate()
 	if err != nil {
 		return err
 	}
-	cli.commander.AddCommand(command.String(), command)
+	_, err = cli.commander.AddCommand(command.String(), command, aliases...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddGlobalOption adds an option accepted by every command, such as --verbose,
+// which handlers read with ParseOption like their own options.
+func (cli *Cli) AddGlobalOption(opt command.CommandOption) error {
+	_, err := cli.commander.AddGlobalOption(opt)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+// SetConfigFiles replaces the configuration files options fall back to, after
+// the environment and before their default value, from the least to the most
+// specific. They default to DefaultConfigFiles. The one given with --config is
+// read last.
+func (cli *Cli) SetConfigFiles(paths ...string) *Cli {
+	cli.commander.SetConfigFiles(paths...)
+	return cli
+}
+
+// SetEnvPrefix makes options fall back to the environment variable named
+// after their command path and label under prefix, e.g. MYCLI_SERVE_PORT for
+// the port option of serve or MYCLI_VERBOSE for the global verbose option
+// under MYCLI_. Given flags take precedence over environment variables, which
+// take precedence over default values.
+func (cli *Cli) SetEnvPrefix(prefix string) *Cli {
+	cli.commander.SetEnvPrefix(prefix)
+	return cli
+}
+
+// SetPrefixMatching allows running commands by any unique prefix of their
+// names or aliases, e.g. "ver" for "version".
+func (cli *Cli) SetPrefixMatching(enabled bool) *Cli {
+	cli.commander.SetPrefixMatching(enabled)
+	return cli
+}
+
+// OnShutdown registers hook to be run when the application exits, after the
+// hooks registered later. Handlers can register their own hooks through
+// their commander.
+func (cli *Cli) OnShutdown(hook command.ShutdownHook) *Cli {
+	cli.commander.AddShutdownHook(hook)
+	return cli
+}
+
+// Run runs the command given in the process arguments, the script piped to
+// the standard input, or else the interactive shell, then exits the process
+// with the exit code of the failing command, if any.
 func (cli *Cli) Run(interactiveMode bool) {
-	args := os.Args
-	if len(args) > 1 {
-		err := cli.commander.Run(args[1:])
+	if code := cli.Execute(interactiveMode); code != clierrors.ExitSuccess {
+		os.Exit(code)
+	}
+}
+
+// Execute is Run returning the exit code instead of exiting the process. The
+// status given to the exit command takes precedence, and the shutdown hooks
+// are run before returning.
+func (cli *Cli) Execute(interactiveMode bool) int {
+	cli.commander.SetName(cli.Name)
+	code := cli.execute(interactiveMode)
+	if status, exiting := cli.commander.ExitStatus(); exiting {
+		code = status
+	}
+	if err := cli.shutdown(); err != nil {
+		cli.commander.Write(err.Display())
+		cli.commander.Write("\n")
+		if code == clierrors.ExitSuccess {
+			code = clierrors.ExitCode(err)
+		}
+	}
+	return code
+}
+
+func (cli *Cli) execute(interactiveMode bool) int {
+	args := os.Args[1:]
+	piped := !readline.IsTerminal(int(os.Stdin.Fd()))
+	globals := slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == continueOnErrorFlag })
+	if piped && cli.commander.GlobalOptionsOnly(globals) {
+		continueOnError := cli.ContinueOnError || len(globals) < len(args)
+		err := cli.runScript("stdin", os.Stdin, globals, continueOnError)
+		if err != nil {
+			cli.commander.Write(err.Display())
+			cli.commander.Write("\n")
+		}
+		return clierrors.ExitCode(err)
+	}
+	if len(args) > 0 {
+		err := cli.runCommand(args)
 		if err != nil {
 			cli.commander.Write(err.Display())
 		}
 		cli.commander.Write("\n")
+		return clierrors.ExitCode(err)
 	} else if !interactiveMode {
 		cli.commander.Write("Interactive shell is disabled!\n")
-	} else {
-		line, err_ := readline.New(cli.Name + "> ")
+		return clierrors.ExitUsage
+	}
+	cli.runShell()
+	return clierrors.ExitSuccess
+}
+
+// shutdown runs the shutdown hooks, abandoning them after ShutdownTimeout.
+func (cli *Cli) shutdown() clierrors.Error {
+	ctx, cancel := context.WithTimeout(context.Background(), cli.ShutdownTimeout)
+	defer cancel()
+	return cli.commander.Shutdown(ctx)
+}
+
+// runShell runs the interactive shell until EOF (Ctrl+D) or the exit command.
+func (cli *Cli) runShell() {
+	history := newHistory(cli.HistoryFile, cli.HistoryLimit)
+	if err := history.load(); err != nil {
+		cli.commander.Write(fmt.Sprintf("Could not load the history: %v\n", err))
+	}
+	state := PromptState{Name: cli.Name, Symbol: cli.Symbol}
+	line, err_ := readline.NewEx(&readline.Config{
+		Prompt:                 cli.renderPrompt(state),
+		AutoComplete:           &completer{commander: cli.commander},
+		HistoryLimit:           cli.HistoryLimit,
+		DisableAutoSaveHistory: true,
+	})
+	if err_ != nil {
+		log.Fatalf("Error initializing readline: %v", err_)
+	}
+	defer line.Close()
+	for _, entry := range history.Entries() {
+		line.SaveHistory(entry)
+	}
+	history.onClear = line.ResetHistory
+	cli.commander.SetHistory(history)
+	defer cli.commander.SetHistory(nil)
+	base := cli.commander.GetOperator()
+	cli.commander.SetOperator(&shellOperator{Operator: base, line: line})
+	defer cli.commander.SetOperator(base)
+	cli.commander.SetInteractive(true)
+	defer cli.commander.SetInteractive(false)
+	for {
+		state.Name, state.Symbol = cli.Name, cli.Symbol
+		state.Context = cli.commander.GetWorkingContext()
+		line.SetPrompt(cli.renderPrompt(state))
+		input, err_ := line.Readline()
+		if err_ == readline.ErrInterrupt {
+			continue // Discard the current line on Ctrl+C
+		}
 		if err_ != nil {
-			log.Fatalf("Error initializing readline: %v", err_)
+			fmt.Println("\nExiting...") // Exit on EOF (Ctrl+D)
+			break
 		}
-		defer line.Close()
-		line.Config.HistoryLimit = cli.HistoryLimit
-		for {
-			input, err_ := line.Readline()
-			if err_ != nil {
-				fmt.Println("\nExiting...") // Exit on EOF (Ctrl+D)
-				break
-			}
+		recorded, err_ := history.add(input)
+		if err_ != nil {
+			cli.commander.Write(fmt.Sprintf("Could not save the history: %v\n", err_))
+		}
+		if recorded {
 			line.SaveHistory(input)
-			trimmedInput := strings.TrimSpace(strings.TrimSuffix(input, "\n"))
-			if trimmedInput == "" {
-				continue
-			}
-			err := cli.commander.Run(parseLine(trimmedInput))
-			if err != nil {
-				cli.commander.Write(err.Display())
-			}
-			cli.commander.Write("\n")
+		}
+		trimmedInput := strings.TrimSpace(strings.TrimSuffix(input, "\n"))
+		if trimmedInput == "" {
+			continue
+		}
+		start := time.Now()
+		err := cli.runCommand(command.ParseLine(trimmedInput))
+		state.Elapsed = time.Since(start)
+		state.Status = clierrors.ExitCode(err)
+		if err != nil {
+			cli.commander.Write(err.Display())
+		}
+		cli.commander.Write("\n")
+		if _, exiting := cli.commander.ExitStatus(); exiting {
+			break
 		}
 	}
 }
 
-// parseLine splits a string by spaces, respecting quoted sections.
-func parseLine(line string) []string {
-	var args []string
-	var currentArg strings.Builder
-	inQuote := false
-	for _, r := range line {
-		switch r {
-		case '"':
-			inQuote = !inQuote
-		case ' ':
-			if inQuote {
-				currentArg.WriteRune(r)
-			} else if currentArg.Len() > 0 {
-				args = append(args, currentArg.String())
-				currentArg.Reset()
-			}
-		default:
-			currentArg.WriteRune(r)
-		}
-	}
-	if currentArg.Len() > 0 {
-		args = append(args, currentArg.String())
-	}
-	return args
-}
\ No newline at end of file
+// runCommand runs a command through the commander, canceling the context handed
+// to its handler when an interrupt signal (Ctrl+C) is received.
+func (cli *Cli) runCommand(args []string) clierrors.Error {
+	ctx, stop := interruptContext()
+	defer stop()
+	return cli.commander.RunContext(ctx, args)
+}
+
+// runScript runs the commands read from script, one per line, after the
+// global options globals, stopping when an interrupt signal (Ctrl+C) is
+// received, and on the first failure unless continueOnError is set.
+func (cli *Cli) runScript(name string, script io.Reader, globals []string, continueOnError bool) clierrors.Error {
+	ctx, stop := interruptContext()
+	defer stop()
+	return command.RunScript(ctx, scriptCommander{Commander: cli.commander, globals: globals}, name, script, continueOnError)
+}
+
+// scriptCommander runs the commands of a script after the global options given
+// along with it, so that they apply to each of them unless overridden.
+type scriptCommander struct {
+	command.Commander
+	globals []string
+}
+
+func (c scriptCommander) RunContext(ctx context.Context, in []string) clierrors.Error {
+	return c.Commander.RunContext(ctx, append(slices.Clone(c.globals), in...))
+}
+
+// interruptContext returns a context canceled by the first interrupt signal.
+// The commands are waited for once canceled, so the next interrupt is left to
+// kill the process, should one of them ignore the context.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
@@ -2,7 +2,11 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,6 +35,37 @@ func (m *mockOperator) Read() (string, errors.Error) {
 	return "", nil
 }
 
+// stubInput implements command.CommandInput outside of the command package,
+// as done to unit test handlers.
+type stubInput struct {
+	options map[string]any
+}
+
+var _ command.CommandInput = stubInput{}
+
+func (s stubInput) ParseArgument(command.CommandArgument) (any, errors.Error) {
+	return nil, nil
+}
+
+func (s stubInput) ParseOption(opt command.CommandOption) (any, errors.Error) {
+	return s.options[opt.Label], nil
+}
+
+func (s stubInput) Commander() command.Commander {
+	return nil
+}
+
+func (s stubInput) String() string {
+	return ""
+}
+
+func TestHandlerWithStubInput(t *testing.T) {
+	var buf mockOperator
+	input := stubInput{options: map[string]any{command.OutputOptionLabel: command.OutputJSON}}
+	assert.NoError(t, command.WriteOutput(input, &buf, []string{"a"}))
+	assert.Equal(t, "[\n  \"a\"\n]", buf.String())
+}
+
 func TestNewCli(t *testing.T) {
 	cli, err := NewCli("test-cli", "0.0.0")
 	assert.NoError(t, err, "No error should occur for valid cli")
@@ -38,6 +73,7 @@ func TestNewCli(t *testing.T) {
 	assert.Equal(t, "test-cli", cli.Name, "Name should match the provided name")
 	assert.Equal(t, DEFAULT_SYMBOL, cli.Symbol, "Symbol should default to '>'")
 	assert.Equal(t, DEFAULT_HISTORY_LIMIT, cli.HistoryLimit, "History limit should default to 100")
+	assert.Equal(t, DefaultHistoryFile("test-cli"), cli.HistoryFile, "History file should default under the state directory")
 	assert.NotNil(t, cli.commander, "Commander should be initialized")
 }
 
@@ -54,7 +90,25 @@ func TestSetVersion_Valid(t *testing.T) {
 	_, err = cli.SetVersion(version)
 
 	assert.NoError(t, err, "No error should occur for valid version format")
-	assert.Equal(t, "v"+version, command.GetVersionString(), "Version should be set correctly")
+	assert.Equal(t, "v"+version, cli.GetVersion(), "Version should be set correctly")
+}
+
+func TestIndependentClis(t *testing.T) {
+	t.Parallel()
+	first, err := NewCli("first-cli", "1.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	second, err := NewCli("second-cli", "2.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+
+	first.AddCommand(command.NewCommand(
+		"only-first",
+		"Only on the first cli",
+		func(command.CommandInput, operator.Operator) errors.Error { return nil },
+	))
+
+	assert.Equal(t, "v1.0.0", first.GetVersion(), "Version should not be shared between clis")
+	assert.Equal(t, "v2.0.0", second.GetVersion(), "Version should not be shared between clis")
+	assert.NotContains(t, second.commander.GetCommands(), "only-first", "Commands should not be shared between clis")
 }
 
 func TestSetVersion_Invalid(t *testing.T) {
@@ -105,6 +159,18 @@ func TestAddCommand_Invalid(t *testing.T) {
 	assert.Contains(t, err.Error(), "command name cannot be empty", "Error message should indicate invalid command")
 }
 
+func TestAddCommand_Taken(t *testing.T) {
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	noop := func(command.CommandInput, operator.Operator) errors.Error { return nil }
+
+	assert.NoError(t, cli.AddCommand(command.NewCommand("list", "List items", noop), "ls"))
+	err = cli.AddCommand(command.NewCommand("lsblk", "List block devices", noop), "ls")
+	assert.IsType(t, &errors.SetupError{}, err, "An alias should not be taken twice")
+	err = cli.AddCommand(command.NewCommand("manual", "Show the manual", noop), "help")
+	assert.IsType(t, &errors.SetupError{}, err, "An alias should not name another command")
+}
+
 func TestRun_NonInteractiveMode(t *testing.T) {
 	cli, err := NewCli("test-cli", "0.0.0")
 	assert.NoError(t, err, "No error should occur for valid cli")
@@ -127,10 +193,98 @@ func TestRun_NonInteractiveMode(t *testing.T) {
 	var buf mockOperator
 	cli.SetOperator(&buf)
 
-	cli.Run(false)
+	code := cli.Execute(false)
 
 	output := buf.String()
 	assert.Contains(t, output, "Hello, world!", "Output should contain the greeting message")
+	assert.Equal(t, errors.ExitUnexpected, code, "The unexpected error of the handler should set the exit code")
+}
+
+// plainError implements errors.Error without errors.ExitCoder.
+type plainError struct{}
+
+func (e *plainError) Error() string {
+	return "plain"
+}
+
+func (e *plainError) Display() string {
+	return "plain"
+}
+
+func TestExecute_ExitCodes(t *testing.T) {
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	cli.AddCommand(
+		command.NewCommand(
+			"fail",
+			"Always fails",
+			func(input command.CommandInput, writer operator.Operator) errors.Error {
+				return errors.New("failed")
+			},
+		),
+	)
+	cli.AddCommand(
+		command.NewCommand(
+			"deny",
+			"Fails with a custom exit code",
+			func(input command.CommandInput, writer operator.Operator) errors.Error {
+				return errors.WithExitCode(errors.New("denied"), 77)
+			},
+		),
+	)
+	cli.AddCommand(
+		command.NewCommand(
+			"plain",
+			"Fails with an error not choosing its exit code",
+			func(input command.CommandInput, writer operator.Operator) errors.Error {
+				return &plainError{}
+			},
+		),
+	)
+	var buf mockOperator
+	cli.SetOperator(&buf)
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"cli", "version"}, errors.ExitSuccess},
+		{[]string{"cli", "unknown"}, errors.ExitUnknownCommand},
+		{[]string{"cli", "version", "--unknown"}, errors.ExitUsage},
+		{[]string{"cli", "fail"}, errors.ExitFailure},
+		{[]string{"cli", "deny"}, 77},
+		{[]string{"cli", "plain"}, errors.ExitFailure},
+		{[]string{"cli", "exit", "3"}, 3},
+	}
+	for _, test := range tests {
+		os.Args = test.args
+		assert.Equal(t, test.code, cli.Execute(false), "Unexpected exit code for %v", test.args[1:])
+	}
+}
+
+func TestExecute_Shutdown(t *testing.T) {
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	var buf mockOperator
+	cli.SetOperator(&buf)
+	var order []string
+	cli.OnShutdown(func(context.Context) error {
+		order = append(order, "first")
+		return nil
+	}).OnShutdown(func(context.Context) error {
+		order = append(order, "second")
+		return fmt.Errorf("cannot flush")
+	})
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"cli", "version"}
+
+	code := cli.Execute(false)
+	assert.Equal(t, []string{"second", "first"}, order, "Hooks should run in reverse registration order")
+	assert.Equal(t, errors.ExitFailure, code, "A failing hook should fail a successful run")
+	assert.Contains(t, buf.String(), "Shutdown failed: cannot flush")
 }
 
 func TestRun_InteractiveMode(t *testing.T) {
@@ -151,12 +305,39 @@ func TestRun_InteractiveMode(t *testing.T) {
 	// Set up a custom writer to capture output
 	var buf mockOperator
 	cli.SetOperator(&buf)
+	cli.HistoryFile = filepath.Join(t.TempDir(), "history")
 
 	// Simulate interactive mode
 	go func() {
-		cli.Run(true)
+		cli.Execute(true)
 	}()
 
 	// TODO: Simulate user input (requires mocking readline or using a library like `os/exec`).
 	// For now, this test ensures the Run method doesn't panic.
 }
+
+func TestRunScript(t *testing.T) {
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	var buf mockOperator
+	cli.SetOperator(&buf)
+
+	script := "# show the version\nversion\n\nunknown\nversion\n"
+	scriptErr := cli.runScript("stdin", strings.NewReader(script), nil, false)
+	assert.Error(t, scriptErr, "The script should stop on the unknown command")
+	assert.Equal(t, "stdin:4: Invalid command: unknown", scriptErr.Display())
+	assert.Equal(t, "v0.0.0\n", buf.String(), "Only the commands before the failure should run")
+
+	buf.output.Reset()
+	scriptErr = cli.runScript("stdin", strings.NewReader(script), nil, true)
+	assert.Equal(t, "1 command(s) of stdin failed", scriptErr.Display())
+	assert.Equal(t, "v0.0.0\nstdin:4: Invalid command: unknown\nv0.0.0\n", buf.String(), "Every command should run")
+
+	buf.output.Reset()
+	globals := []string{"--output", "json"}
+	assert.True(t, cli.commander.GlobalOptionsOnly(globals))
+	assert.False(t, cli.commander.GlobalOptionsOnly([]string{"--output", "json", "version"}))
+	scriptErr = cli.runScript("stdin", strings.NewReader("version\nversion --output text\n"), globals, false)
+	assert.Nil(t, scriptErr)
+	assert.Equal(t, "{\n  \"name\": \"test-cli\",\n  \"version\": \"0.0.0\"\n}\nv0.0.0\n", buf.String(), "The global options should apply to every command unless overridden")
+}
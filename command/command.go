@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"slices"
@@ -24,6 +25,60 @@ type CommandArgument struct {
 	Description string
 	Position    int
 	ValueType   ValueType
+	// Choices lists the accepted values of a TypeEnum argument.
+	Choices []string
+	// Completer provides the completion candidates of the argument when they
+	// are only known at runtime.
+	Completer CompletionFunc
+	// Optional arguments may be omitted, in which case Default is used if set.
+	Optional bool
+	Default  string
+	// Variadic arguments take every surplus positional token and parse to a
+	// typed slice. A command can have at most one of them.
+	Variadic bool
+	// Env names the environment variable the argument falls back to when it
+	// is not given.
+	Env string
+}
+
+// usage renders the argument as shown in the help: "arg", "[arg]", "arg..."
+// or "[arg...]".
+func (a CommandArgument) usage() string {
+	label := a.Label
+	if a.Variadic {
+		label += "..."
+	}
+	if a.Optional {
+		label = "[" + label + "]"
+	}
+	return label
+}
+
+// help renders the argument as listed under its command in help.
+func (a CommandArgument) help() string {
+	usage := a.usage()
+	if typeName := TypeName(a.ValueType); typeName != "" {
+		usage += " <" + typeName + ">"
+	}
+	description := describeValue(a.Description, a.ValueType, a.Choices, a.Default)
+	if a.Env != "" {
+		description += " (env: " + a.Env + ")"
+	}
+	return fmt.Sprintf("\t   %s:  %s.\n", usage, description)
+}
+
+// describeValue completes the description of an argument or option with the
+// values it accepts and its default value.
+func describeValue(description string, valueType ValueType, choices []string, def string) string {
+	if valueType == TypeEnum {
+		description += " (one of: " + strings.Join(choices, ", ") + ")"
+	} else if hint := typeHint(valueType); hint != "" {
+		description += " (e.g. " + hint + ")"
+	}
+	if def != "" {
+		description += " (default: " + def + ")"
+	}
+	return description
 }
 
 type CommandOption struct {
@@ -32,17 +87,100 @@ type CommandOption struct {
 	Letter      rune
 	Name        string
 	ValueType   ValueType
+	// Choices lists the accepted values of a TypeEnum option.
+	Choices []string
+	// Completer provides the completion candidates of the option value when
+	// they are only known at runtime.
+	Completer CompletionFunc
+	// Default is used when the option is not given.
+	Default string
+	// Required options need to be given for the command to run.
+	Required bool
+	// Env names the environment variable the option falls back to when it is
+	// not given, before its default value. Options without one fall back to
+	// the variable named after their command path and label under the env
+	// prefix, if set, e.g. MYCLI_SERVE_PORT, or after their label only for
+	// global options.
+	Env string
+	// ConfigKey is the dotted path of the option in the configuration files,
+	// read after the environment. It defaults to the path of its command
+	// followed by its label, e.g. "serve.port", or to its label for global
+	// options.
+	ConfigKey string
+}
+
+// flag renders the option as typed on the command line, preferring its name.
+func (o CommandOption) flag() string {
+	if o.Name != "" {
+		return OptionNamePrefix + o.Name
+	}
+	return OptionLetterPrefix + string(o.Letter)
+}
+
+// help renders the option as listed under its command in help.
+func (o CommandOption) help() string {
+	var names []string
+	if o.Letter != 0 {
+		names = append(names, OptionLetterPrefix+string(o.Letter))
+	}
+	if o.Name != "" {
+		names = append(names, OptionNamePrefix+o.Name)
+	}
+	// Options without flags are only set from the environment or the
+	// configuration, so they are listed by their label.
+	flags := o.Label
+	if len(names) > 0 {
+		flags = strings.Join(names, " | ")
+	}
+	if typeName := TypeName(o.ValueType); typeName != "" {
+		flags += " <" + typeName + ">"
+	}
+	description := describeValue(o.Description, o.ValueType, o.Choices, o.Default)
+	if o.Required {
+		description += " (required)"
+	}
+	if o.Env != "" {
+		description += " (env: " + o.Env + ")"
+	}
+	return fmt.Sprintf("\t   %s:  %s.\n", flags, description)
 }
 
 type CommandInput interface {
 	ParseArgument(CommandArgument) (any, errors.Error)
 	ParseOption(CommandOption) (any, errors.Error)
+	Commander() Commander
 	String() string
 }
 
 type commandInput struct {
 	arguments map[string]any
 	options   map[string]any
+	commander Commander
+}
+
+// Commander returns the commander that is running the command, giving
+// handlers access to its commands and configuration.
+func (c *commandInput) Commander() Commander {
+	return c.commander
+}
+
+func (c *commandInput) setCommander(commander Commander) *commandInput {
+	c.commander = commander
+	return c
+}
+
+// addOptions adds the values of options the command did not set itself, such
+// as global ones.
+func (c *commandInput) addOptions(options map[string]any) *commandInput {
+	if c.options == nil {
+		c.options = make(map[string]any)
+	}
+	for label, value := range options {
+		if _, exists := c.options[label]; !exists {
+			c.options[label] = value
+		}
+	}
+	return c
 }
 
 func (c *commandInput) String() string {
@@ -51,7 +189,15 @@ func (c *commandInput) String() string {
 
 func (c *commandInput) ParseArgument(arg CommandArgument) (any, errors.Error) {
 	argValue := c.arguments[arg.Label]
-	argValue, err := ParseValue(arg.ValueType, argValue)
+	if argValue == nil {
+		return nil, nil
+	}
+	var err error
+	if values, ok := argValue.([]string); ok {
+		argValue, err = ParseValues(arg.ValueType, values)
+	} else {
+		argValue, err = ParseValue(arg.ValueType, argValue)
+	}
 	if err != nil {
 		return nil, &CommandError{message: "Invalid type for argument: " + arg.Label}
 	}
@@ -75,24 +221,72 @@ func (c *commandInput) ParseOption(opt CommandOption) (any, errors.Error) {
 
 type CommandHanlder func(CommandInput, operator.Operator) errors.Error
 
+// ContextCommandHandler is a command handler that also receives a context,
+// canceled when the command gets interrupted (e.g. on Ctrl+C).
+type ContextCommandHandler func(context.Context, CommandInput, operator.Operator) errors.Error
+
+// WithContext adapts a CommandHanlder into a ContextCommandHandler that
+// ignores the context.
+func (h CommandHanlder) WithContext() ContextCommandHandler {
+	if h == nil {
+		return nil
+	}
+	return func(_ context.Context, input CommandInput, operator operator.Operator) errors.Error {
+		return h(input, operator)
+	}
+}
+
 type Command interface {
 	setName(string) Command
+	addAliases(...string) Command
+	GetAliases() []string
+	setParent(Command) Command
+	setEnvPrefix(string) Command
+	setConfig(*Config) Command
 	AddArgument(CommandArgument) (Command, errors.Error)
 	AddOption(CommandOption) (Command, errors.Error)
+	AddOptionGroup(OptionGroupKind, ...string) (Command, errors.Error)
+	AddSubCommand(Command, ...string) (Command, errors.Error)
+	GetSubCommand(string) (Command, bool)
+	GetSubCommands() []string
+	SetHidden(bool) Command
+	IsHidden() bool
 	setHandler(CommandHanlder) Command
+	setContextHandler(ContextCommandHandler) Command
 	Validate() errors.Error
 	Handle(CommandInput, operator.Operator) errors.Error
+	HandleContext(context.Context, CommandInput, operator.Operator) errors.Error
 	Parse([]string) (CommandInput, errors.Error)
+	parseOptions([]string, *command) (map[string]any, map[string]any, []string, errors.Error)
+	parseInput(map[string]any, []string, argumentPrompter) (CommandInput, errors.Error)
 	String() string
+	Path() string
+	Usage() string
 	Help() string
 }
 
 type command struct {
-	Name        string
-	Arguments   []CommandArgument
-	Options     []CommandOption
-	handler     CommandHanlder
-	Description string
+	Name      string
+	Arguments []CommandArgument
+	Options   []CommandOption
+	// OptionGroups constrain how options can be used together.
+	OptionGroups []OptionGroup
+	SubCommands  map[string]Command
+	parent       Command
+	handler      ContextCommandHandler
+	Description  string
+	// Aliases are alternative names the command can be run with.
+	Aliases []string
+	// Hidden commands can be run but are left out of help and completion.
+	Hidden bool
+	// envPrefix names the environment variables the options of top level
+	// commands and their subcommands fall back to.
+	envPrefix string
+	// config holds the configuration the options of top level commands and
+	// their subcommands fall back to, loaded for the running command.
+	config *Config
+	// global marks the command holding the global options.
+	global bool
 }
 
 func NewCommand(name string, description string, handler CommandHanlder) Command {
@@ -104,59 +298,215 @@ func NewCommand(name string, description string, handler CommandHanlder) Command
 	return command
 }
 
+func NewContextCommand(name string, description string, handler ContextCommandHandler) Command {
+	command := &command{
+		Name:        name,
+		Description: description,
+	}
+	command.setContextHandler(handler)
+	return command
+}
+
+func (c *command) addAliases(aliases ...string) Command {
+	for _, alias := range aliases {
+		alias = strings.ToLower(alias)
+		if !slices.Contains(c.Aliases, alias) {
+			c.Aliases = append(c.Aliases, alias)
+		}
+	}
+	return c
+}
+
+func (c *command) GetAliases() []string {
+	return c.Aliases
+}
+
 func (c *command) String() string {
 	return c.Name
 }
 
-func (c *command) Help() string {
+// Path returns the space separated chain of command names leading to this
+// command, e.g. "user add" for the "add" subcommand of "user".
+func (c *command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// Usage returns how the command is typed, e.g. "user add name [options]".
+func (c *command) Usage() string {
 	usageBuilder := &strings.Builder{}
-	usageBuilder.WriteString("Usage: > " + c.Name)
-	if len(c.Arguments) > 0 {
-		for _, arg := range c.Arguments {
-			usageBuilder.WriteString(" " + arg.Label)
+	usageBuilder.WriteString(c.Path())
+	if len(c.SubCommands) > 0 {
+		if c.handler == nil {
+			usageBuilder.WriteString(" <command>")
+		} else {
+			usageBuilder.WriteString(" [command]")
 		}
 	}
+	for _, arg := range c.sortedArguments() {
+		usageBuilder.WriteString(" " + arg.usage())
+	}
 	if len(c.Options) > 0 {
 		usageBuilder.WriteString(" [options]")
 	}
+	return usageBuilder.String()
+}
 
-	helpText := fmt.Sprintf("\t- %-15s %s\n", c.Name+":", c.Description+" ["+usageBuilder.String()+"]")
-	if len(c.Options) > 0 {
-		optionsBuilder := &strings.Builder{}
-		for _, opt := range c.Options {
-			optionsBuilder.WriteString(fmt.Sprintf("\t   -%c | --%s:  %s.\n", opt.Letter, opt.Label, opt.Description))
+func (c *command) Help() string {
+	name := c.Name
+	if len(c.Aliases) > 0 {
+		name += " (" + strings.Join(c.Aliases, ", ") + ")"
+	}
+	helpText := fmt.Sprintf("\t- %-15s %s\n", name+":", c.Description+" [Usage: > "+c.Usage()+"]")
+	for _, arg := range c.sortedArguments() {
+		helpText += arg.help()
+	}
+	for _, opt := range c.Options {
+		helpText += c.withEnv(opt).help()
+	}
+	helpText += c.groupsHelp()
+	for _, name := range c.GetSubCommands() {
+		if sub := c.SubCommands[name]; !sub.IsHidden() {
+			helpText += indent(sub.Help(), "  ")
 		}
-		helpText += optionsBuilder.String()
 	}
 	return helpText
 }
 
+// indent inserts prefix after the leading tab of every line of a help text so
+// that subcommands render nested under their parent.
+func indent(text string, prefix string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			lines[i] = "\t" + prefix + line[1:]
+		}
+	}
+	return strings.Join(lines, "")
+}
+
 func (c *command) setName(name string) Command {
 	c.Name = name
 	return c
 }
 
+func (c *command) SetHidden(hidden bool) Command {
+	c.Hidden = hidden
+	return c
+}
+
+func (c *command) IsHidden() bool {
+	return c.Hidden
+}
+
+func (c *command) setParent(parent Command) Command {
+	c.parent = parent
+	return c
+}
+
 func (c *command) AddArgument(arg CommandArgument) (Command, errors.Error) {
 	for _, argument := range c.Arguments {
 		if argument.Label == arg.Label {
 			return nil, errors.NewSetupError(fmt.Sprintf("Argument %s for command %s already exists!", arg.Label, c.Name))
 		}
+		if argument.Variadic && arg.Variadic {
+			return nil, errors.NewSetupError(fmt.Sprintf("Argument %s for command %s cannot be variadic, %s already is!", arg.Label, c.Name, argument.Label))
+		}
+	}
+	if arg.ValueType == TypeEnum && len(arg.Choices) == 0 {
+		return nil, errors.NewSetupError(fmt.Sprintf("Argument %s for command %s needs choices to be an enum!", arg.Label, c.Name))
+	}
+	if arg.Default != "" {
+		if !arg.Optional {
+			return nil, errors.NewSetupError(fmt.Sprintf("Argument %s for command %s needs to be optional to have a default value!", arg.Label, c.Name))
+		}
+		if err := validateValue(arg.ValueType, arg.Choices, arg.Default); err != nil {
+			return nil, errors.NewSetupError(fmt.Sprintf("Default value %s of argument %s for command %s is invalid: %s", arg.Default, arg.Label, c.Name, err))
+		}
 	}
 	c.Arguments = append(c.Arguments, arg)
 	return c, nil
 }
 
+// sortedArguments returns the arguments of the command ordered by position.
+func (c *command) sortedArguments() []CommandArgument {
+	return slices.SortedStableFunc(slices.Values(c.Arguments), func(a, b CommandArgument) int {
+		return a.Position - b.Position
+	})
+}
+
 func (c *command) AddOption(opt CommandOption) (Command, errors.Error) {
 	for _, option := range c.Options {
 		if option.Label == opt.Label {
 			return nil, errors.NewSetupError(fmt.Sprintf("Argument %s for command %s already exists!", opt.Label, c.Name))
 		}
 	}
+	if opt.ValueType == TypeEnum && len(opt.Choices) == 0 {
+		return nil, errors.NewSetupError(fmt.Sprintf("Option %s for command %s needs choices to be an enum!", opt.Label, c.Name))
+	}
+	if opt.Required && opt.Default != "" {
+		return nil, errors.NewSetupError(fmt.Sprintf("Option %s for command %s is required and cannot have a default value!", opt.Label, c.Name))
+	}
+	if opt.Default != "" {
+		if opt.ValueType == NoType {
+			return nil, errors.NewSetupError(fmt.Sprintf("Option %s for command %s is a flag and cannot have a default value!", opt.Label, c.Name))
+		}
+		if err := validateValue(opt.ValueType, opt.Choices, opt.Default); err != nil {
+			return nil, errors.NewSetupError(fmt.Sprintf("Default value %s of option %s for command %s is invalid: %s", opt.Default, opt.Label, c.Name, err))
+		}
+	}
 	c.Options = append(c.Options, opt)
 	return c, nil
 }
 
+// AddSubCommand adds sub as a child of the command, which can also be run
+// under any of aliases.
+func (c *command) AddSubCommand(sub Command, aliases ...string) (Command, errors.Error) {
+	err := sub.Validate()
+	if err != nil {
+		return nil, err
+	}
+	name := strings.ToLower(sub.String())
+	for _, taken := range append([]string{name}, aliases...) {
+		if _, exists := c.GetSubCommand(taken); exists {
+			return nil, errors.NewSetupError(fmt.Sprintf("Subcommand %s for command %s already exists!", strings.ToLower(taken), c.Name))
+		}
+	}
+	if c.SubCommands == nil {
+		c.SubCommands = make(map[string]Command)
+	}
+	sub.setParent(c)
+	sub.addAliases(aliases...)
+	c.SubCommands[name] = sub
+	return c, nil
+}
+
+// GetSubCommand returns the subcommand called name, or having it as an alias.
+func (c *command) GetSubCommand(name string) (Command, bool) {
+	name = strings.ToLower(name)
+	if sub, exists := c.SubCommands[name]; exists {
+		return sub, true
+	}
+	for _, sub := range c.SubCommands {
+		if slices.Contains(sub.GetAliases(), name) {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+func (c *command) GetSubCommands() []string {
+	return slices.Sorted(maps.Keys(c.SubCommands))
+}
+
 func (c *command) setHandler(commandHandler CommandHanlder) Command {
+	c.handler = commandHandler.WithContext()
+	return c
+}
+
+func (c *command) setContextHandler(commandHandler ContextCommandHandler) Command {
 	c.handler = commandHandler
 	return c
 }
@@ -171,112 +521,351 @@ func (c *command) Validate() errors.Error {
 	if len(strings.Split(c.Description, " ")) < 2 {
 		return errors.NewSetupError(fmt.Sprintf("Command %s is invalid, needs to have atleast 2 words long in its description: %s!", c.Name, c.Description))
 	}
-	if c.handler == nil {
-		return errors.NewSetupError(fmt.Sprintf("Command %s is not properly set up, needs to have a handler!", c.Name))
+	if c.handler == nil && len(c.SubCommands) == 0 {
+		return errors.NewSetupError(fmt.Sprintf("Command %s is not properly set up, needs to have a handler or subcommands!", c.Name))
+	}
+	for _, sub := range c.SubCommands {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (c *command) Handle(input CommandInput, operator operator.Operator) errors.Error {
-	return c.handler(input, operator)
+	return c.HandleContext(context.Background(), input, operator)
+}
+
+func (c *command) HandleContext(ctx context.Context, input CommandInput, operator operator.Operator) errors.Error {
+	if c.handler == nil {
+		return &InvalidCommandUsageError{command: c}
+	}
+	return c.handler(ctx, input, operator)
+}
+
+type Commander interface {
+	Get(string) (Command, bool)
+	Resolve([]string) (Command, []string, errors.Error)
+	AddCommand(string, Command, ...string) (Commander, errors.Error)
+	GetCommands() []string
+	GetName() string
+	SetName(string) Commander
+	GetVersion() string
+	SetVersion(string) Commander
+	GetHelpText() string
+	SetHelpText(string) Commander
+	GetHistory() History
+	SetHistory(History) Commander
+	GetWorkingContext() string
+	SetWorkingContext(string) Commander
+	Exit(int) Commander
+	ExitStatus() (int, bool)
+	AddShutdownHook(ShutdownHook) Commander
+	Shutdown(context.Context) errors.Error
+	SetSuggestionDistance(int) Commander
+	SetPrefixMatching(bool) Commander
+	SetInteractive(bool) Commander
+	SetEnvPrefix(string) Commander
+	SetConfigFiles(...string) Commander
+	GetConfig() *Config
+	AddGlobalOption(CommandOption) (Commander, errors.Error)
+	GetGlobalOptions() []CommandOption
+	GlobalOptionsOnly([]string) bool
+	SetOperator(operator.Operator) Commander
+	GetOperator() operator.Operator
+	Write(string) errors.Error
+	Run([]string) errors.Error
+	RunContext(context.Context, []string) errors.Error
 }
 
-func (c *command) Parse(input []string) (CommandInput, errors.Error) {
-	inputLength := len(input)
-	inputArgs := make(map[string]any)
-	inputOpts := make(map[string]any)
+type commander struct {
+	commands map[string]Command
+	// aliases maps the alternative names of commands to their names.
+	aliases map[string]string
+	// prefixMatching allows running commands by any unique prefix of their
+	// names or aliases.
+	prefixMatching bool
+	// globals holds the global options, accepted by every command.
+	globals   *command
+	envPrefix string
+	// configFiles are read in order, before the one given with the config
+	// global option, into config when running a command.
+	configFiles []string
+	config      *Config
+	operator    operator.Operator
+	name        string
+	version     string
+	helpText    string
+	history     History
+	// workingContext is set by commands to scope the ones that follow, e.g.
+	// to a selected project, and is shown in the shell prompt.
+	workingContext string
+	exitStatus     int
+	exiting        bool
+	shutdownHooks  []ShutdownHook
+	// suggestionDistance bounds the edits between an unknown command or flag
+	// and the ones suggested in its place, 0 disabling suggestions.
+	suggestionDistance int
+	// interactive makes the commands prompt for their missing arguments.
+	interactive bool
+}
 
-	// Parse arguments
-	nbrArguments := len(c.Arguments)
-	if inputLength < nbrArguments {
-		return nil, &InvalidCommandUsageError{command: c}
+func NewCommander() Commander {
+	return &commander{
+		commands:           make(map[string]Command),
+		aliases:            make(map[string]string),
+		globals:            &command{Description: "Global options.", global: true},
+		suggestionDistance: DefaultSuggestionDistance,
 	}
-	for _, arg := range c.Arguments {
-		value, err := ParseValue(arg.ValueType, input[arg.Position])
-		if err != nil {
-			return nil, &InvalidCommandUsageError{command: c}
+}
+
+// AddCommand registers command under commandName, and under each of aliases,
+// none of which may already name or alias another command.
+func (c *commander) AddCommand(commandName string, command Command, aliases ...string) (Commander, errors.Error) {
+	name := strings.ToLower(commandName)
+	for _, taken := range append([]string{name}, aliases...) {
+		taken = strings.ToLower(taken)
+		_, isName := c.commands[taken]
+		_, isAlias := c.aliases[taken]
+		if isName || isAlias {
+			return nil, errors.NewSetupError(fmt.Sprintf("Command %s already exists!", taken))
 		}
-		inputArgs[arg.Label] = value
-		input = slices.Delete(input, arg.Position, arg.Position+1)
 	}
+	command.setName(commandName)
+	command.addAliases(aliases...)
+	command.setEnvPrefix(c.envPrefix)
+	c.commands[name] = command
+	for _, alias := range aliases {
+		c.aliases[strings.ToLower(alias)] = name
+	}
+	return c, nil
+}
 
-	// Parse options
-	for _, opt := range c.Options {
-		index := slices.Index(input, OptionLetterPrefix+string(opt.Letter))
-		if index == -1 {
-			index = slices.Index(input, OptionNamePrefix+opt.Name)
+// Get returns the command called commandName, or having it as an alias, or,
+// when prefix matching is enabled, the only command it is a prefix of.
+func (c *commander) Get(commandName string) (Command, bool) {
+	command, err := c.lookup(commandName)
+	return command, err == nil
+}
+
+func (c *commander) lookup(commandName string) (Command, errors.Error) {
+	if command, exists := c.commands[commandName]; exists {
+		return command, nil
+	}
+	if name, exists := c.aliases[commandName]; exists {
+		if command, exists := c.commands[name]; exists {
+			return command, nil
 		}
-		if index != -1 {
-			if opt.ValueType == NoType {
-				inputOpts[opt.Label] = true
-				input = slices.Delete(input, index, index)
-			} else {
-				if index+1 >= inputLength {
-					return nil, &InvalidCommandUsageError{command: c}
-				}
-				value, err := ParseValue(opt.ValueType, input[index+1])
-				if err != nil {
-					return nil, &InvalidCommandUsageError{command: c}
+	}
+	if c.prefixMatching && commandName != "" {
+		var candidates []string
+		for _, name := range c.visibleCommands() {
+			if !strings.HasPrefix(name, commandName) {
+				continue
+			}
+			if canonical, isAlias := c.aliases[name]; isAlias {
+				if _, exists := c.commands[canonical]; exists {
+					name = canonical
 				}
-				inputOpts[opt.Label] = value
-				input = slices.Delete(input, index, index+2)
 			}
+			if !slices.Contains(candidates, name) {
+				candidates = append(candidates, name)
+			}
+		}
+		if len(candidates) == 1 {
+			return c.commands[candidates[0]], nil
+		}
+		if len(candidates) > 1 {
+			slices.Sort(candidates)
+			return nil, &AmbiguousCommandError{command: commandName, candidates: candidates}
 		}
 	}
+	return nil, &InvalidCommandError{command: commandName, suggestions: suggest(commandName, c.visibleCommands(), c.suggestionDistance)}
+}
+
+func (c *commander) GetCommands() []string {
+	return slices.Collect(maps.Keys(c.commands))
+}
 
-	if len(input) > 0 {
-		if strings.HasPrefix(input[0], OptionLetterPrefix) || strings.HasPrefix(input[0], OptionNamePrefix) {
-			return nil, &UnreconizedFlagError{command: c.Name, flag: input[0]}
+// visibleCommands returns the names and aliases of the commands that are not
+// hidden.
+func (c *commander) visibleCommands() []string {
+	var names []string
+	for name, command := range c.commands {
+		if !command.IsHidden() {
+			names = append(names, name)
 		}
-		return nil, &InvalidCommandUsageError{command: c}
 	}
+	for alias, name := range c.aliases {
+		if command, exists := c.commands[name]; exists && !command.IsHidden() {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
 
-	return &commandInput{
-		arguments: inputArgs,
-		options:   inputOpts,
-	}, nil
+// GetName returns the name of the program, as typed to run it from a shell.
+func (c *commander) GetName() string {
+	return c.name
 }
 
-type Commander interface {
-	Get(string) (Command, bool)
-	AddCommand(string, Command) Commander
-	GetCommands() []string
-	SetOperator(operator.Operator) Commander
-	Write(string) errors.Error
-	Run([]string) errors.Error
+func (c *commander) SetName(name string) Commander {
+	c.name = name
+	return c
 }
 
-type commander struct {
-	commands map[string]Command
-	operator operator.Operator
+func (c *commander) GetVersion() string {
+	return c.version
+}
+
+func (c *commander) SetVersion(version string) Commander {
+	c.version = version
+	return c
+}
+
+func (c *commander) GetHelpText() string {
+	return c.helpText
+}
+
+func (c *commander) SetHelpText(helpText string) Commander {
+	c.helpText = helpText
+	return c
+}
+
+func (c *commander) GetHistory() History {
+	return c.history
+}
+
+func (c *commander) SetHistory(history History) Commander {
+	c.history = history
+	return c
+}
+
+func (c *commander) GetWorkingContext() string {
+	return c.workingContext
+}
+
+func (c *commander) SetWorkingContext(workingContext string) Commander {
+	c.workingContext = workingContext
+	return c
+}
+
+// Exit asks the application to stop once the running command returns, with
+// status as its exit status.
+func (c *commander) Exit(status int) Commander {
+	c.exitStatus = status
+	c.exiting = true
+	return c
+}
+
+// ExitStatus returns the status passed to Exit, and whether it was called.
+func (c *commander) ExitStatus() (int, bool) {
+	return c.exitStatus, c.exiting
+}
+
+// AddShutdownHook registers hook to be run by Shutdown when the application
+// exits.
+func (c *commander) AddShutdownHook(hook ShutdownHook) Commander {
+	c.shutdownHooks = append(c.shutdownHooks, hook)
+	return c
+}
+
+// SetSuggestionDistance sets the largest number of edits between an unknown
+// command or flag and the ones suggested in its place, 0 disabling suggestions.
+func (c *commander) SetSuggestionDistance(distance int) Commander {
+	c.suggestionDistance = distance
+	return c
+}
+
+// SetPrefixMatching allows running commands by any unique prefix of their
+// names or aliases, e.g. "ver" for "version".
+func (c *commander) SetPrefixMatching(enabled bool) Commander {
+	c.prefixMatching = enabled
+	return c
+}
+
+// SetInteractive makes the commands prompt, through the operator, for the
+// required arguments missing from their input, as they do when given the
+// interactive global option.
+func (c *commander) SetInteractive(enabled bool) Commander {
+	c.interactive = enabled
+	return c
+}
+
+// AddGlobalOption adds an option accepted by every command, anywhere before
+// the options terminator. Handlers read it with ParseOption like their own
+// options. After the command name, the flags a command declares are its own,
+// and a command declaring its label never sees the global value.
+func (c *commander) AddGlobalOption(opt CommandOption) (Commander, errors.Error) {
+	if opt.Required {
+		return nil, errors.NewSetupError(fmt.Sprintf("Global option %s cannot be required!", opt.Label))
+	}
+	if _, err := c.globals.AddOption(opt); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
-var commanderInstance Commander
+// GlobalOptionsOnly reports whether in only holds global options and their
+// values, such as the arguments given along with a script piped to the
+// application.
+func (c *commander) GlobalOptionsOnly(in []string) bool {
+	return c.globals.leadingOptions(in) == len(in)
+}
 
-func GetCommander() Commander {
-	if commanderInstance == nil {
-		commanderInstance = &commander{commands: make(map[string]Command)}
-		return commanderInstance
+// GetGlobalOptions returns the global options, along with the environment
+// variables they fall back to.
+func (c *commander) GetGlobalOptions() []CommandOption {
+	options := make([]CommandOption, 0, len(c.globals.Options))
+	for _, opt := range c.globals.Options {
+		options = append(options, c.globals.withEnv(opt))
 	}
-	return commanderInstance
+	return options
 }
 
-func (c *commander) AddCommand(commandName string, command Command) Commander {
-	command.setName(commandName)
-	c.commands[strings.ToLower(commandName)] = command
+// SetConfigFiles sets the configuration files options fall back to, after
+// the environment, from the least to the most specific.
+func (c *commander) SetConfigFiles(paths ...string) Commander {
+	c.configFiles = paths
 	return c
 }
 
-func (c *commander) Get(commandName string) (Command, bool) {
-	command := c.commands[commandName]
-	if command == nil {
-		return nil, false
+// GetConfig returns the configuration loaded for the running command.
+func (c *commander) GetConfig() *Config {
+	return c.config
+}
+
+// loadConfig reads the configuration files, followed by the one given with
+// the config global option if any.
+func (c *commander) loadConfig(globals map[string]any) errors.Error {
+	paths := slices.Clone(c.configFiles)
+	if path, ok := globals[ConfigOptionLabel].(string); ok {
+		paths = append(paths, path)
+	}
+	config, err := LoadConfig(paths...)
+	if err != nil {
+		return err
 	}
-	return command, true
+	c.useConfig(config)
+	return nil
 }
 
-func (c *commander) GetCommands() []string {
-	return slices.Collect(maps.Keys(c.commands))
+func (c *commander) useConfig(config *Config) {
+	c.config = config
+	c.globals.setConfig(config)
+}
+
+// SetEnvPrefix makes options fall back to the environment variable named
+// after their command path and label under prefix, e.g. MYCLI_SERVE_PORT for
+// the port option of serve or MYCLI_VERBOSE for the global verbose option
+// under MYCLI_, when they name none themselves.
+func (c *commander) SetEnvPrefix(prefix string) Commander {
+	c.envPrefix = prefix
+	c.globals.setEnvPrefix(prefix)
+	for _, command := range c.commands {
+		command.setEnvPrefix(prefix)
+	}
+	return c
 }
 
 func (c *commander) SetOperator(operator operator.Operator) Commander {
@@ -284,6 +873,10 @@ func (c *commander) SetOperator(operator operator.Operator) Commander {
 	return c
 }
 
+func (c *commander) GetOperator() operator.Operator {
+	return c.operator
+}
+
 func (c *commander) Write(output string) errors.Error {
 	err := c.operator.Write(output)
 	if err != nil {
@@ -292,16 +885,118 @@ func (c *commander) Write(output string) errors.Error {
 	return nil
 }
 
-func (c *commander) Run(in []string) errors.Error {
-	commandName := strings.ToLower(in[0])
-	command, exists := c.Get(commandName)
-	if !exists {
-		return &InvalidCommandError{command: commandName}
+// Resolve walks the command tree by consuming leading tokens of in that name a
+// command then its nested subcommands. It returns the deepest matching command
+// along with the remaining tokens, or an InvalidCommandError when the next
+// token can only be a subcommand but names none.
+func (c *commander) Resolve(in []string) (Command, []string, errors.Error) {
+	if len(in) == 0 {
+		return nil, nil, &InvalidCommandError{command: ""}
+	}
+	command, err := c.lookup(strings.ToLower(in[0]))
+	if err != nil {
+		return nil, nil, err
 	}
 	input := in[1:]
-	inputCommand, err := command.Parse(input)
+	for len(input) > 0 {
+		sub, exists := command.GetSubCommand(input[0])
+		if !exists {
+			if err := unknownSubCommand(command, input[0], c.suggestionDistance); err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+		command = sub
+		input = input[1:]
+	}
+	return command, input, nil
+}
+
+// runScope is what a running command hands down to the commands it runs in
+// turn, such as those of a script, through the context of its handler.
+type runScope struct {
+	// options are the global options given to the command, which the commands
+	// it runs inherit unless they are given their own.
+	options map[string]any
+	config  *Config
+}
+
+type runScopeKey struct{}
+
+func (c *commander) Run(in []string) errors.Error {
+	return c.RunContext(context.Background(), in)
+}
+
+// RunContext runs the command named by in, handing ctx to its handler along
+// with the global options, which are taken out of in first. Options not given
+// fall back to the environment, then to the configuration files, then to their
+// default value. Commands run by a handler, such as those of a script, inherit
+// the global options and configuration files given to it. When ctx is
+// canceled, RunContext still waits for the handler to return, so that it never
+// runs alongside the next command, and returns an InterruptedError.
+func (c *commander) RunContext(ctx context.Context, in []string) errors.Error {
+	c.globals.Name = c.name
+	globals, command, input, err := c.globals.resolveAfterOptions(in, c.Resolve)
+	if err != nil {
+		return err
+	}
+	// Every option given before the command name is a global one, while after
+	// it the global options are shadowed by those of the command.
+	local := c.globals.shadowedBy(command)
+	inputOpts, trailing, positionals, err := command.parseOptions(input, local)
+	if flagErr, ok := err.(*UnreconizedFlagError); ok {
+		flagErr.suggestions = suggest(flagErr.flag, flagErr.flags, c.suggestionDistance)
+	}
+	if err != nil {
+		return err
+	}
+	for label := range globals {
+		if _, exists := local.findOption(label); !exists {
+			delete(globals, label)
+		}
+	}
+	maps.Copy(globals, trailing)
+	_, ownConfig := globals[ConfigOptionLabel]
+	outer, nested := ctx.Value(runScopeKey{}).(*runScope)
+	if nested {
+		for label, value := range outer.options {
+			if _, exists := local.findOption(label); exists {
+				if _, given := globals[label]; !given {
+					globals[label] = value
+				}
+			}
+		}
+		defer c.useConfig(outer.config)
+	}
+	scope := &runScope{options: maps.Clone(globals)}
+	if err := local.setFromEnv(globals); err != nil {
+		return err
+	}
+	if nested && !ownConfig {
+		c.useConfig(outer.config)
+	} else if err := c.loadConfig(globals); err != nil {
+		return err
+	}
+	scope.config = c.config
+	local.setConfig(c.config)
+	if err := local.setFromConfig(globals); err != nil {
+		return err
+	}
+	local.setDefaults(globals)
+	command.setConfig(c.config)
+	var prompt argumentPrompter
+	if c.interactive || globals[InteractiveOptionLabel] == true {
+		prompt = c.promptArgument(ctx, command.Path())
+	}
+	inputCommand, err := command.parseInput(inputOpts, positionals, prompt)
 	if err != nil {
 		return err
 	}
-	return command.Handle(inputCommand, c.operator)
+	// Inputs are only ever built by parseInput
+	handlerInput := inputCommand.(*commandInput).addOptions(globals).setCommander(c)
+	err = command.HandleContext(context.WithValue(ctx, runScopeKey{}, scope), handlerInput, c.operator)
+	if ctx.Err() != nil {
+		return &InterruptedError{command: command.Path()}
+	}
+	return err
 }
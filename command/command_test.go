@@ -2,8 +2,10 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/cli/operator"
@@ -14,12 +16,12 @@ func createExitCommand() Command {
 	return exitCmd
 }
 
-func createVersionCommand(v string) Command {
-	return VersionCommand(v)
+func createVersionCommand() Command {
+	return VersionCommand()
 }
 
 func createHelpCommand() Command {
-	helpCmd := HelpCommand("")
+	helpCmd := HelpCommand()
 	return helpCmd
 }
 
@@ -173,7 +175,8 @@ func TestCommandExecution(t *testing.T) {
 }
 
 func TestCommander(t *testing.T) {
-	commander := GetCommander()
+	t.Parallel()
+	commander := NewCommander()
 	writer := &mockOperator{}
 
 	t.Run("Add and Get Command", func(t *testing.T) {
@@ -209,6 +212,22 @@ func TestCommander(t *testing.T) {
 		}
 	})
 
+	t.Run("Independent Commanders", func(t *testing.T) {
+		other := NewCommander()
+		if _, exists := other.Get("runTest"); exists {
+			t.Fatal("Expected commands to not be shared between commanders")
+		}
+		other.SetVersion("2.0.0")
+		commander.SetVersion("1.0.0")
+		if other.GetVersion() != "2.0.0" {
+			t.Errorf("Expected version '2.0.0', but got '%s'", other.GetVersion())
+		}
+		commander.SetWorkingContext("prod")
+		if other.GetWorkingContext() != "" {
+			t.Errorf("Expected no working context, but got '%s'", other.GetWorkingContext())
+		}
+	})
+
 	t.Run("Run Invalid Command", func(t *testing.T) {
 		err := commander.Run([]string{"invalidCmd"})
 		if err == nil {
@@ -286,3 +305,261 @@ func TestDuplicateArgumentsAndOptions(t *testing.T) {
 		}
 	})
 }
+
+func createUserCommand() Command {
+	user := NewCommand("user", "Manage the users.", nil)
+	add := NewCommand("add", "Add a user.", func(input CommandInput, operator operator.Operator) errors.Error {
+		name, err := input.ParseArgument(CommandArgument{Label: "name", ValueType: TypeString})
+		if err != nil {
+			return err
+		}
+		operator.Write("added " + name.(string))
+		return nil
+	})
+	add.AddArgument(CommandArgument{Label: "name", Description: "Name of the user", Position: 0, ValueType: TypeString})
+	list := NewCommand("list", "List the users.", func(_ CommandInput, operator operator.Operator) errors.Error {
+		operator.Write("listed")
+		return nil
+	})
+	if _, err := user.AddSubCommand(add); err != nil {
+		panic(err)
+	}
+	if _, err := user.AddSubCommand(list); err != nil {
+		panic(err)
+	}
+	return user
+}
+
+func TestSubCommands(t *testing.T) {
+	t.Parallel()
+	commander := NewCommander()
+	writer := &mockOperator{}
+	commander.SetOperator(writer)
+	commander.AddCommand("user", createUserCommand())
+
+	t.Run("Validate Parent Without Handler", func(t *testing.T) {
+		user, _ := commander.Get("user")
+		if err := user.Validate(); err != nil {
+			t.Fatalf("Expected no error validating parent command, but got: %v", err)
+		}
+		empty := NewCommand("empty", "Has no handler.", nil)
+		if err := empty.Validate(); err == nil {
+			t.Fatal("Expected an error for command without handler nor subcommands, but got none")
+		}
+	})
+
+	t.Run("Duplicate Subcommand", func(t *testing.T) {
+		user, _ := commander.Get("user")
+		_, err := user.AddSubCommand(NewCommand("list", "List the users.", func(CommandInput, operator.Operator) errors.Error { return nil }))
+		if _, ok := err.(*errors.SetupError); !ok {
+			t.Errorf("Expected SetupError, but got %T", err)
+		}
+	})
+
+	t.Run("Run Nested Command", func(t *testing.T) {
+		writer.Reset()
+		err := commander.Run([]string{"user", "add", "alice"})
+		if err != nil {
+			t.Fatalf("Expected no error running nested command, but got: %v", err)
+		}
+		if writer.String() != "added alice" {
+			t.Errorf("Unexpected output: %s", writer.String())
+		}
+	})
+
+	t.Run("Resolve Path", func(t *testing.T) {
+		cmd, rest, err := commander.Resolve([]string{"user", "LIST", "extra"})
+		if err != nil {
+			t.Fatalf("Expected no error resolving command, but got: %v", err)
+		}
+		if cmd.Path() != "user list" {
+			t.Errorf("Expected path 'user list', but got '%s'", cmd.Path())
+		}
+		if len(rest) != 1 || rest[0] != "extra" {
+			t.Errorf("Unexpected remaining tokens: %v", rest)
+		}
+	})
+
+	t.Run("Run Parent Without Handler", func(t *testing.T) {
+		err := commander.Run([]string{"user"})
+		if _, ok := err.(*InvalidCommandUsageError); !ok {
+			t.Errorf("Expected InvalidCommandUsageError, but got %T", err)
+		}
+	})
+
+	t.Run("Help Renders Hierarchy", func(t *testing.T) {
+		user, _ := commander.Get("user")
+		help := user.Help()
+		if !strings.Contains(help, "Usage: > user <command>") {
+			t.Errorf("Expected parent usage in help, got: %s", help)
+		}
+		if !strings.Contains(help, "\t  - add:") || !strings.Contains(help, "Usage: > user add name") {
+			t.Errorf("Expected nested subcommand in help, got: %s", help)
+		}
+	})
+}
+
+func TestContextHandlers(t *testing.T) {
+	t.Parallel()
+	commander := NewCommander()
+	writer := &mockOperator{}
+	commander.SetOperator(writer)
+
+	commander.AddCommand("wait", NewContextCommand("wait", "Wait for cancellation.", func(ctx context.Context, _ CommandInput, _ operator.Operator) errors.Error {
+		<-ctx.Done()
+		return &CommandError{message: "canceled"}
+	}))
+	block := make(chan struct{})
+	commander.AddCommand("block", NewCommand("block", "Ignore the context.", func(CommandInput, operator.Operator) errors.Error {
+		<-block
+		return nil
+	}))
+
+	t.Run("Legacy Handler Adapted", func(t *testing.T) {
+		comm := createSampleCommand()
+		err := comm.HandleContext(context.Background(), &commandInput{
+			arguments: map[string]any{"arg1": "a"},
+			options:   map[string]any{"opt1": "b"},
+		}, writer)
+		if err != nil {
+			t.Fatalf("Expected no error during execution, but got: %v", err)
+		}
+	})
+
+	t.Run("Cancel Context Aware Handler", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := commander.RunContext(ctx, []string{"wait"})
+		if err == nil {
+			t.Fatal("Expected an error for canceled command, but got none")
+		}
+	})
+
+	t.Run("Wait For Handler Ignoring Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		done := make(chan errors.Error, 1)
+		go func() {
+			done <- commander.RunContext(ctx, []string{"block"})
+		}()
+		select {
+		case <-done:
+			t.Fatal("Expected RunContext to wait for the handler to return")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(block)
+		err := <-done
+		if _, ok := err.(*InterruptedError); !ok {
+			t.Errorf("Expected InterruptedError, but got %T", err)
+		}
+		if err != nil && errors.ExitCode(err) != errors.ExitInterrupted {
+			t.Errorf("Expected exit code %d, but got %d", errors.ExitInterrupted, errors.ExitCode(err))
+		}
+	})
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+	writer := &mockOperator{}
+	commander := NewCommander().SetOperator(writer)
+	user := createUserCommand()
+	commander.AddCommand("user", user, "u")
+	commander.AddCommand("version", VersionCommand(), "v")
+	remove := NewCommand("remove", "Remove a user.", func(_ CommandInput, operator operator.Operator) errors.Error {
+		operator.Write("removed")
+		return nil
+	})
+	if _, err := user.AddSubCommand(remove, "rm", "delete"); err != nil {
+		t.Fatalf("Expected no error adding an aliased subcommand, but got: %v", err)
+	}
+
+	t.Run("Command Alias", func(t *testing.T) {
+		writer.Reset()
+		cmd, exists := commander.Get("v")
+		if !exists || cmd.String() != "version" {
+			t.Fatalf("Expected alias v to get the version command, but got %v", cmd)
+		}
+		if err := commander.Run([]string{"u", "list"}); err != nil {
+			t.Fatalf("Expected no error running an aliased command, but got: %v", err)
+		}
+		if writer.String() != "listed" {
+			t.Errorf("Unexpected output: %s", writer.String())
+		}
+	})
+
+	t.Run("Subcommand Alias", func(t *testing.T) {
+		writer.Reset()
+		if err := commander.Run([]string{"user", "rm"}); err != nil {
+			t.Fatalf("Expected no error running an aliased subcommand, but got: %v", err)
+		}
+		if writer.String() != "removed" {
+			t.Errorf("Unexpected output: %s", writer.String())
+		}
+	})
+
+	t.Run("Duplicate Subcommand Alias", func(t *testing.T) {
+		drop := NewCommand("drop", "Drop a user.", func(CommandInput, operator.Operator) errors.Error { return nil })
+		if _, err := user.AddSubCommand(drop, "rm"); err == nil {
+			t.Fatal("Expected an error for a taken alias, but got none")
+		}
+	})
+
+	t.Run("Duplicate Command Alias", func(t *testing.T) {
+		noop := func(CommandInput, operator.Operator) errors.Error { return nil }
+		if _, err := commander.AddCommand("users", NewCommand("users", "List users.", noop), "u"); err == nil {
+			t.Fatal("Expected an error for a taken alias, but got none")
+		}
+		if _, err := commander.AddCommand("usage", NewCommand("usage", "Show usage.", noop), "user"); err == nil {
+			t.Fatal("Expected an error for an alias naming a command, but got none")
+		}
+		if _, err := commander.AddCommand("V", NewCommand("V", "Show the version.", noop)); err == nil {
+			t.Fatal("Expected an error for a name taken as an alias, but got none")
+		}
+		if cmd, _ := commander.Get("u"); cmd.String() != "user" {
+			t.Errorf("Expected alias u to still get the user command, but got %v", cmd)
+		}
+	})
+
+	t.Run("Help Shows Aliases", func(t *testing.T) {
+		help := user.Help()
+		if !strings.Contains(help, "- user (u):") || !strings.Contains(help, "- remove (rm, delete):") {
+			t.Errorf("Expected aliases in help, got: %s", help)
+		}
+	})
+
+	t.Run("Prefixes Disabled By Default", func(t *testing.T) {
+		if _, _, err := commander.Resolve([]string{"vers"}); err == nil {
+			t.Fatal("Expected an error for a prefix, but got none")
+		}
+	})
+
+	t.Run("Unique Prefix", func(t *testing.T) {
+		commander := NewCommander().SetPrefixMatching(true)
+		commander.AddCommand("version", VersionCommand(), "ver")
+		commander.AddCommand("verify", NewCommand("verify", "Verify the setup.", func(CommandInput, operator.Operator) errors.Error { return nil }))
+		commander.AddCommand(CompleteCommandName, CompleteCommand())
+
+		for prefix, expected := range map[string]string{"vers": "version", "veri": "verify", "ver": "version"} {
+			cmd, _, err := commander.Resolve([]string{prefix})
+			if err != nil {
+				t.Fatalf("Expected no error resolving %s, but got: %v", prefix, err)
+			}
+			if cmd.String() != expected {
+				t.Errorf("Expected %s to resolve to %s, but got %s", prefix, expected, cmd.String())
+			}
+		}
+
+		_, _, err := commander.Resolve([]string{"ve"})
+		if _, ok := err.(*AmbiguousCommandError); !ok {
+			t.Fatalf("Expected AmbiguousCommandError, but got %T", err)
+		}
+		if err.Display() != "Ambiguous command: ve matches verify, version" {
+			t.Errorf("Unexpected error: %s", err.Display())
+		}
+
+		_, _, err = commander.Resolve([]string{"__"})
+		if _, ok := err.(*InvalidCommandError); !ok {
+			t.Errorf("Expected hidden commands to not match prefixes, but got %T", err)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package command
+
+import (
+	"slices"
+	"strings"
+)
+
+// Completion is a candidate for the word being completed.
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// CompletionFunc returns the candidates for an argument or option value
+// starting with prefix, for values that can only be known at runtime.
+type CompletionFunc func(prefix string) []Completion
+
+// Complete returns the candidates for the last of words, given the words typed
+// before it. It walks the command tree of commander like Run does, then offers
+// subcommand names, option flags or values depending on where the word sits.
+// The returned bool reports whether the word expects a file path, for callers
+// able to fall back to file completion.
+func Complete(commander Commander, words []string) ([]Completion, bool) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	before := words[:len(words)-1]
+	globals := &command{Options: commander.GetGlobalOptions()}
+	if globals.leadingOptions(before) == len(before) {
+		// Only global options were typed, so the word is the value of the last
+		// one, when it still expects it, or else the command name
+		if _, pending, _ := globals.scanWords(before); pending != nil {
+			return filterCompletions(valueCompletions(pending.ValueType, pending.Choices, pending.Completer, current), current), pending.ValueType == TypeFile
+		}
+		var completions []Completion
+		for _, name := range commander.GetCommands() {
+			if cmd, _ := commander.Get(name); !cmd.IsHidden() {
+				completions = append(completions, commandCompletion(name, cmd))
+			}
+		}
+		return filterCompletions(completions, current), false
+	}
+	_, cmd, rest, err := globals.resolveAfterOptions(before, commander.Resolve)
+	if err != nil {
+		return nil, false
+	}
+	c, ok := cmd.(*command)
+	if !ok {
+		return nil, false
+	}
+	// Global options are accepted like the options of the command, unless it
+	// declares the same flags
+	c = c.withOptions(globals.shadowedBy(c).Options)
+
+	positionals, pending, terminated := c.scanWords(rest)
+	if pending != nil {
+		return filterCompletions(valueCompletions(pending.ValueType, pending.Choices, pending.Completer, current), current), pending.ValueType == TypeFile
+	}
+	if !terminated && strings.HasPrefix(current, OptionLetterPrefix) {
+		if name, _, hasValue := strings.Cut(strings.TrimPrefix(current, OptionNamePrefix), "="); hasValue && strings.HasPrefix(current, OptionNamePrefix) {
+			opt, exists := c.findOptionByName(name)
+			if !exists {
+				return nil, false
+			}
+			var completions []Completion
+			_, prefix, _ := strings.Cut(current, "=")
+			for _, completion := range valueCompletions(opt.ValueType, opt.Choices, opt.Completer, prefix) {
+				completions = append(completions, Completion{Value: OptionNamePrefix + name + "=" + completion.Value, Description: completion.Description})
+			}
+			return filterCompletions(completions, current), false
+		}
+		return filterCompletions(c.flagCompletions(), current), false
+	}
+
+	var completions []Completion
+	if positionals == 0 && len(rest) == 0 {
+		for _, name := range c.GetSubCommands() {
+			if sub := c.SubCommands[name]; !sub.IsHidden() {
+				completions = append(completions, commandCompletion(name, sub))
+			}
+		}
+	}
+	files := false
+	if arg, exists := c.argumentAt(positionals); exists {
+		completions = append(completions, valueCompletions(arg.ValueType, arg.Choices, arg.Completer, current)...)
+		files = arg.ValueType == TypeFile
+	}
+	return filterCompletions(completions, current), files
+}
+
+// scanWords walks the words typed after the command name and returns how many
+// positional arguments they hold, the option whose value is expected next if
+// any, and whether the options terminator was seen.
+func (c *command) scanWords(words []string) (int, *CommandOption, bool) {
+	positionals := 0
+	terminated := false
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		var valued *CommandOption
+		switch {
+		case terminated:
+			positionals++
+		case word == OptionsTerminator:
+			terminated = true
+		case strings.HasPrefix(word, OptionNamePrefix):
+			name, _, hasValue := strings.Cut(strings.TrimPrefix(word, OptionNamePrefix), "=")
+			if opt, exists := c.findOptionByName(name); exists && opt.ValueType != NoType && !hasValue {
+				valued = &opt
+			}
+		case strings.HasPrefix(word, OptionLetterPrefix) && word != OptionLetterPrefix && !c.isNegativeNumber(word):
+			letters := []rune(strings.TrimPrefix(word, OptionLetterPrefix))
+			for j, letter := range letters {
+				if opt, exists := c.findOptionByLetter(letter); exists && opt.ValueType != NoType {
+					if j == len(letters)-1 {
+						valued = &opt
+					}
+					break
+				}
+			}
+		default:
+			positionals++
+		}
+		if valued != nil {
+			if i == len(words)-1 {
+				return positionals, valued, terminated
+			}
+			i++
+		}
+	}
+	return positionals, nil, terminated
+}
+
+// argumentAt returns the argument receiving the positional token at index.
+func (c *command) argumentAt(index int) (CommandArgument, bool) {
+	arguments := c.sortedArguments()
+	for i, arg := range arguments {
+		if arg.Variadic {
+			// Tokens after the variadic argument go to the following ones, which
+			// cannot be told apart while typing, so keep completing it.
+			return arg, index >= i
+		}
+		if i == index {
+			return arg, true
+		}
+	}
+	return CommandArgument{}, false
+}
+
+func commandCompletion(name string, cmd Command) Completion {
+	completion := Completion{Value: name}
+	if c, ok := cmd.(*command); ok {
+		completion.Description = c.Description
+	}
+	return completion
+}
+
+func (c *command) flagCompletions() []Completion {
+	var completions []Completion
+	for _, opt := range c.Options {
+		if opt.Name != "" {
+			completions = append(completions, Completion{Value: OptionNamePrefix + opt.Name, Description: opt.Description})
+		}
+		if opt.Letter != 0 {
+			completions = append(completions, Completion{Value: OptionLetterPrefix + string(opt.Letter), Description: opt.Description})
+		}
+	}
+	return completions
+}
+
+// valueCompletions lists the values of an argument or option, from its
+// completer if it has one, else from those known in advance for its type.
+func valueCompletions(valueType ValueType, choices []string, completer CompletionFunc, prefix string) []Completion {
+	if completer != nil {
+		return completer(prefix)
+	}
+	var values []string
+	switch valueType {
+	case TypeEnum:
+		values = choices
+	case TypeBool:
+		values = []string{"true", "false"}
+	}
+	completions := make([]Completion, 0, len(values))
+	for _, value := range values {
+		completions = append(completions, Completion{Value: value})
+	}
+	return completions
+}
+
+func filterCompletions(completions []Completion, prefix string) []Completion {
+	filtered := slices.DeleteFunc(completions, func(completion Completion) bool {
+		return !strings.HasPrefix(completion.Value, prefix)
+	})
+	slices.SortFunc(filtered, func(a, b Completion) int {
+		return strings.Compare(a.Value, b.Value)
+	})
+	return filtered
+}
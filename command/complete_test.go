@@ -0,0 +1,110 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func values(completions []Completion) []string {
+	result := []string{}
+	for _, completion := range completions {
+		result = append(result, completion.Value)
+	}
+	return result
+}
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+	commander := NewCommander()
+	handler := func(CommandInput, operator.Operator) errors.Error { return nil }
+	deploy := NewCommand("deploy", "Deploy the application.", handler)
+	deploy.AddArgument(CommandArgument{Label: "env", Position: 0, ValueType: TypeEnum, Choices: []string{"dev", "prod"}})
+	deploy.AddOption(CommandOption{Label: "manifest", Letter: 'm', Name: "manifest", ValueType: TypeFile, Description: "Manifest to apply"})
+	deploy.AddOption(CommandOption{Label: "wait", Letter: 'w', Name: "wait", ValueType: TypeBool})
+	deploy.AddOption(CommandOption{Label: "verbose", Letter: 'v', Name: "verbose"})
+	commander.AddCommand("deploy", deploy)
+	commander.AddCommand("user", createUserCommand())
+	commander.AddCommand(CompleteCommandName, CompleteCommand())
+
+	t.Run("Command Names", func(t *testing.T) {
+		completions, files := Complete(commander, []string{""})
+		assert.Equal(t, []string{"deploy", "user"}, values(completions), "hidden commands are not completed")
+		assert.False(t, files)
+		assert.Equal(t, "Deploy the application.", completions[0].Description)
+	})
+
+	t.Run("Subcommand Names", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"user", "l"})
+		assert.Equal(t, []string{"list"}, values(completions))
+	})
+
+	t.Run("Argument Choices", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"deploy", "-v", ""})
+		assert.Equal(t, []string{"dev", "prod"}, values(completions))
+	})
+
+	t.Run("Flags", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"deploy", "--"})
+		assert.Equal(t, []string{"--manifest", "--verbose", "--wait"}, values(completions))
+		completions, _ = Complete(commander, []string{"deploy", "-"})
+		assert.Len(t, completions, 6)
+	})
+
+	t.Run("Global Options", func(t *testing.T) {
+		commander := NewCommander()
+		commander.AddGlobalOption(CommandOption{Label: "output", Name: "output", ValueType: TypeEnum, Choices: []string{"json", "text"}})
+		commander.AddCommand("user", createUserCommand())
+		completions, _ := Complete(commander, []string{"--output", "json", "us"})
+		assert.Equal(t, []string{"user"}, values(completions))
+		completions, _ = Complete(commander, []string{"--output", ""})
+		assert.Equal(t, []string{"json", "text"}, values(completions), "The value of a global option before the command should be completed")
+		completions, _ = Complete(commander, []string{"--output", "j"})
+		assert.Equal(t, []string{"json"}, values(completions))
+		completions, _ = Complete(commander, []string{"user", "add", "--o"})
+		assert.Equal(t, []string{"--output"}, values(completions))
+		completions, _ = Complete(commander, []string{"user", "add", "--output", ""})
+		assert.Equal(t, []string{"json", "text"}, values(completions))
+	})
+
+	t.Run("Option Values", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"deploy", "--wait", ""})
+		assert.Equal(t, []string{"false", "true"}, values(completions))
+		completions, _ = Complete(commander, []string{"deploy", "--wait=t"})
+		assert.Equal(t, []string{"--wait=true"}, values(completions))
+	})
+
+	t.Run("File Values", func(t *testing.T) {
+		completions, files := Complete(commander, []string{"deploy", "-m", ""})
+		assert.Empty(t, completions)
+		assert.True(t, files)
+	})
+
+	t.Run("After Options Terminator", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"deploy", "--", "-"})
+		assert.Empty(t, completions)
+	})
+
+	t.Run("Dynamic Option Values", func(t *testing.T) {
+		var prefixes []string
+		tag := NewCommand("tag", "Tag a release.", handler)
+		tag.AddOption(CommandOption{Label: "release", Letter: 'r', Name: "release", ValueType: TypeString, Completer: func(prefix string) []Completion {
+			prefixes = append(prefixes, prefix)
+			return []Completion{{Value: "v1.0", Description: "stable"}, {Value: "v2.0-rc"}}
+		}})
+		commander.AddCommand("tag", tag)
+
+		completions, _ := Complete(commander, []string{"tag", "-r", "v2"})
+		assert.Equal(t, []string{"v2.0-rc"}, values(completions))
+		completions, _ = Complete(commander, []string{"tag", "--release=v1"})
+		assert.Equal(t, []Completion{{Value: "--release=v1.0", Description: "stable"}}, completions)
+		assert.Equal(t, []string{"v2", "v1"}, prefixes)
+	})
+
+	t.Run("Unknown Command", func(t *testing.T) {
+		completions, _ := Complete(commander, []string{"unknown", ""})
+		assert.Empty(t, completions)
+	})
+}
@@ -0,0 +1,175 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// CompleteCommandName is the hidden command the completion scripts call back
+// into with the words typed so far.
+const CompleteCommandName = "__complete"
+
+// Directives ending the output of the complete command, telling the shell
+// script what to do with the candidates.
+const (
+	completionDirectiveNone  = ":0"
+	completionDirectiveFiles = ":1"
+)
+
+var shellArg = CommandArgument{
+	Label:       "shell",
+	Description: "Shell to generate the completion script for",
+	Position:    0,
+	ValueType:   TypeEnum,
+	Choices:     []string{"bash", "zsh", "fish"},
+}
+
+var wordsArg = CommandArgument{
+	Label:       "words",
+	Description: "Words typed so far, the last one being completed",
+	Position:    0,
+	ValueType:   TypeString,
+	Optional:    true,
+	Variadic:    true,
+}
+
+func completionHandler(input CommandInput, operator operator.Operator) errors.Error {
+	shell, err := input.ParseArgument(shellArg)
+	if err != nil {
+		return err
+	}
+	name := input.Commander().GetName()
+	function := "__" + regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(name, "_") + "_complete"
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletionScript
+	case "zsh":
+		script = zshCompletionScript
+	case "fish":
+		script = fishCompletionScript
+	}
+	script = strings.NewReplacer("{{name}}", name, "{{function}}", function, "{{complete}}", CompleteCommandName).Replace(script)
+	err_ := operator.Write(script)
+	if err_ != nil {
+		return errors.NewUnexpectedError(err_)
+	}
+	return nil
+}
+
+func completeHandler(input CommandInput, operator operator.Operator) errors.Error {
+	words, err := input.ParseArgument(wordsArg)
+	if err != nil {
+		return err
+	}
+	completions, files := Complete(input.Commander(), words.([]string))
+	output := &strings.Builder{}
+	for _, completion := range completions {
+		if completion.Description != "" {
+			fmt.Fprintf(output, "%s\t%s\n", completion.Value, completion.Description)
+		} else {
+			fmt.Fprintf(output, "%s\n", completion.Value)
+		}
+	}
+	if files {
+		output.WriteString(completionDirectiveFiles)
+	} else {
+		output.WriteString(completionDirectiveNone)
+	}
+	err_ := operator.Write(output.String())
+	if err_ != nil {
+		return errors.NewUnexpectedError(err_)
+	}
+	return nil
+}
+
+func CompletionCommand() Command {
+	cmd := NewCommand(
+		"completion",
+		"Generate the shell completion script for bash, zsh or fish.",
+		completionHandler,
+	)
+	cmd.AddArgument(shellArg)
+	return cmd
+}
+
+// CompleteCommand is the hidden entry point of the completion scripts, it
+// prints the candidates for the words typed so far, one per line.
+func CompleteCommand() Command {
+	cmd := NewCommand(
+		CompleteCommandName,
+		"List the completions of a command line.",
+		completeHandler,
+	)
+	cmd.AddArgument(wordsArg)
+	return cmd.SetHidden(true)
+}
+
+const bashCompletionScript = `# bash completion for {{name}}
+# Load it with: source <({{name}} completion bash)
+{{function}}() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local line directive=0
+    local -a candidates=()
+    while IFS= read -r line; do
+        case "$line" in
+            :*) directive="${line#:}" ;;
+            "") ;;
+            *) candidates+=("${line%%$'\t'*}") ;;
+        esac
+    done < <({{name}} {{complete}} -- "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+    if [[ ${#candidates[@]} -eq 0 && "$directive" == 1 ]]; then
+        COMPREPLY=($(compgen -f -- "$cur"))
+    else
+        COMPREPLY=("${candidates[@]}")
+    fi
+}
+complete -F {{function}} {{name}}
+`
+
+const zshCompletionScript = `#compdef {{name}}
+# zsh completion for {{name}}
+# Load it with: source <({{name}} completion zsh)
+{{function}}() {
+    local line directive=0
+    local -a candidates
+    for line in "${(@f)$({{name}} {{complete}} -- "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        case "$line" in
+            :*) directive="${line#:}" ;;
+            "") ;;
+            *) candidates+=("${${line//:/\\:}/$'\t'/:}") ;;
+        esac
+    done
+    if (( ${#candidates} == 0 && directive == 1 )); then
+        _files
+    else
+        _describe 'values' candidates
+    fi
+}
+compdef {{function}} {{name}}
+`
+
+const fishCompletionScript = `# fish completion for {{name}}
+# Load it with: {{name}} completion fish | source
+function {{function}}
+    set -l words (commandline -opc)[2..-1] (commandline -ct)
+    set -l directive 0
+    for line in ({{name}} {{complete}} -- $words 2>/dev/null)
+        switch $line
+            case ':*'
+                set directive (string sub -s 2 -- $line)
+            case ''
+            case '*'
+                echo $line
+        end
+    end
+    if test "$directive" = 1
+        __fish_complete_path (commandline -ct)
+    end
+end
+complete -c {{name}} -f -a '({{function}})'
+`
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionCommand(t *testing.T) {
+	t.Parallel()
+	writer := &mockOperator{}
+	commander := NewCommander().SetName("my-cli").SetOperator(writer)
+	commander.AddCommand("completion", CompletionCommand())
+	commander.AddCommand(CompleteCommandName, CompleteCommand())
+	commander.AddCommand("version", createVersionCommand())
+
+	t.Run("Scripts", func(t *testing.T) {
+		for shell, expected := range map[string]string{
+			"bash": "complete -F __my_cli_complete my-cli",
+			"zsh":  "compdef __my_cli_complete my-cli",
+			"fish": "complete -c my-cli -f -a '(__my_cli_complete)'",
+		} {
+			writer.Reset()
+			err := commander.Run([]string{"completion", shell})
+			assert.NoError(t, err)
+			assert.Contains(t, writer.String(), expected)
+			assert.Contains(t, writer.String(), "my-cli __complete --")
+		}
+	})
+
+	t.Run("Unsupported Shell", func(t *testing.T) {
+		err := commander.Run([]string{"completion", "tcsh"})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+
+	t.Run("Complete Entry Point", func(t *testing.T) {
+		writer.Reset()
+		err := commander.Run([]string{CompleteCommandName, "--", "ver"})
+		assert.NoError(t, err)
+		assert.Equal(t, "version\tDisplay the current version.\n:0", writer.String())
+	})
+
+	t.Run("Hidden From Help", func(t *testing.T) {
+		writer.Reset()
+		commander.AddCommand("help", createHelpCommand())
+		err := commander.Run([]string{"help"})
+		assert.NoError(t, err)
+		assert.NotContains(t, writer.String(), CompleteCommandName)
+		assert.Contains(t, writer.String(), "completion")
+	})
+}
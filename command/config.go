@@ -0,0 +1,186 @@
+package command
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigOptionLabel labels the global option naming a configuration file read
+// after the default ones.
+const ConfigOptionLabel = "config"
+
+// ConfigFileOption is the global option naming a configuration file read
+// after the default ones.
+var ConfigFileOption = CommandOption{
+	Label:       ConfigOptionLabel,
+	Name:        "config",
+	ValueType:   TypeFile,
+	Description: "Configuration file read after the default ones",
+}
+
+// DefaultConfigFiles returns the configuration files of the program called
+// name, from the least to the most specific: the system one, the one of the
+// user and the one of the project in the working directory.
+func DefaultConfigFiles(name string) []string {
+	files := []string{filepath.Join("/etc", name, "config.yaml")}
+	if dir, err := os.UserConfigDir(); err == nil {
+		files = append(files, filepath.Join(dir, name, "config.yaml"))
+	}
+	return append(files, "."+name+".yaml")
+}
+
+type configLayer struct {
+	path   string
+	values map[string]any
+}
+
+// Config holds the values of layered configuration files, the later layers
+// overriding the earlier ones. Files are written in YAML, or JSON which YAML
+// includes, and options are keyed into their tree by dotted paths such as
+// "serve.port".
+type Config struct {
+	layers []configLayer
+}
+
+// LoadConfig reads the configuration files at paths, in order, skipping those
+// that do not exist.
+func LoadConfig(paths ...string) (*Config, errors.Error) {
+	config := &Config{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, &ConfigError{path: path, err: err}
+		}
+		values := make(map[string]any)
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, &ConfigError{path: path, err: err}
+		}
+		config.layers = append(config.layers, configLayer{path: path, values: values})
+	}
+	return config, nil
+}
+
+// Files returns the paths of the configuration files that were read.
+func (c *Config) Files() []string {
+	if c == nil {
+		return nil
+	}
+	var files []string
+	for _, layer := range c.layers {
+		files = append(files, layer.path)
+	}
+	return files
+}
+
+// Lookup returns the value at key, a dotted path into the configuration tree,
+// along with the file it was read from. Lists are returned comma-separated and
+// maps as comma-separated key=value pairs, as options expect them.
+func (c *Config) Lookup(key string) (string, string, bool) {
+	if c == nil {
+		return "", "", false
+	}
+	for i := len(c.layers) - 1; i >= 0; i-- {
+		value, exists := lookupKey(c.layers[i].values, strings.Split(key, "."))
+		if exists {
+			return configString(value), c.layers[i].path, true
+		}
+	}
+	return "", "", false
+}
+
+func lookupKey(values map[string]any, path []string) (any, bool) {
+	value, exists := values[path[0]]
+	if !exists || value == nil {
+		return nil, false
+	}
+	if len(path) == 1 {
+		return value, true
+	}
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupKey(nested, path[1:])
+}
+
+func configString(value any) string {
+	switch value := value.(type) {
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, configString(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		pairs := make([]string, 0, len(value))
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			pairs = append(pairs, key+"="+configString(value[key]))
+		}
+		return strings.Join(pairs, ",")
+	case time.Time:
+		return value.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (c *command) setConfig(config *Config) Command {
+	c.root().config = config
+	return c
+}
+
+// configKey returns the key of opt in the configuration tree: its own, or the
+// path of the command followed by its label, e.g. "serve.port".
+func (c *command) configKey(opt CommandOption) string {
+	if opt.ConfigKey != "" {
+		return opt.ConfigKey
+	}
+	if c.global {
+		return opt.Label
+	}
+	return strings.ReplaceAll(c.Path(), " ", ".") + "." + opt.Label
+}
+
+// setFromConfig sets the options that were not given from the configuration.
+// Flags are set when it holds a true boolean.
+func (c *command) setFromConfig(inputOpts map[string]any) errors.Error {
+	config := c.root().config
+	for _, opt := range c.Options {
+		if _, given := inputOpts[opt.Label]; given {
+			continue
+		}
+		key := c.configKey(opt)
+		value, path, exists := config.Lookup(key)
+		if !exists {
+			continue
+		}
+		name := key + " in " + path
+		if opt.ValueType == NoType {
+			set, err := strconv.ParseBool(value)
+			if err != nil {
+				return newInvalidValueError(c, "configuration key", name, TypeBool, value, err)
+			}
+			if set {
+				inputOpts[opt.Label] = true
+			}
+			continue
+		}
+		if err := validateValue(opt.ValueType, opt.Choices, value); err != nil {
+			return newInvalidValueError(c, "configuration key", name, opt.ValueType, value, err)
+		}
+		inputOpts[opt.Label] = value
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// optionSource returns the value opt falls back to when it is not given, from
+// the environment, config or its default, and where the value comes from.
+func (c *command) optionSource(opt CommandOption, config *Config) (string, string) {
+	opt = c.withEnv(opt)
+	if value, exists := lookupEnv(opt.Env); exists {
+		return value, "env " + opt.Env
+	}
+	if value, path, exists := config.Lookup(c.configKey(opt)); exists {
+		return value, path
+	}
+	if opt.Default != "" {
+		return opt.Default, "default"
+	}
+	return "", "unset"
+}
+
+// optionSourceOutput is a row of the structured output of config.
+type optionSourceOutput struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// optionSources appends a row per option of c, then of its subcommands.
+func optionSources(sources []optionSourceOutput, c *command, config *Config) []optionSourceOutput {
+	for _, opt := range c.Options {
+		value, source := c.optionSource(opt, config)
+		sources = append(sources, optionSourceOutput{Key: c.configKey(opt), Value: value, Source: source})
+	}
+	for _, name := range c.GetSubCommands() {
+		if sub, ok := c.SubCommands[name].(*command); ok && !sub.IsHidden() {
+			sources = optionSources(sources, sub, config)
+		}
+	}
+	return sources
+}
+
+func configHandler(input CommandInput, operator operator.Operator) errors.Error {
+	commander := input.Commander()
+	config := commander.GetConfig()
+	sources := optionSources(nil, &command{Options: commander.GetGlobalOptions(), global: true}, config)
+	cmds := commander.GetCommands()
+	slices.Sort(cmds)
+	for _, name := range cmds {
+		if cmd, _ := commander.Get(name); !cmd.IsHidden() {
+			if c, ok := cmd.(*command); ok {
+				sources = optionSources(sources, c, config)
+			}
+		}
+	}
+	if OutputFormat(input) != OutputText {
+		return WriteOutput(input, operator, sources)
+	}
+	// The table is rendered for the operator like those of WriteOutput, then
+	// followed by the configuration files.
+	text := &strings.Builder{}
+	text.WriteString(newTable(sources).RenderString(operator))
+	if files := config.Files(); len(files) > 0 {
+		fmt.Fprintf(text, "\nConfiguration files: %s\n", strings.Join(files, ", "))
+	} else {
+		text.WriteString("\nNo configuration file found\n")
+	}
+	return operator.Write(text.String())
+}
+
+// ConfigCommand shows the value every option falls back to when it is not
+// given, and whether it comes from the environment, a configuration file or
+// its default.
+func ConfigCommand() Command {
+	return NewCommand(
+		"config",
+		"Show the effective value and source of every option.",
+		configHandler,
+	)
+}
@@ -0,0 +1,140 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	system := writeConfigFile(t, "system.yaml", "serve:\n  port: 80\n  host: example.com\nverbose: true\n")
+	user := writeConfigFile(t, "user.json", `{"serve": {"port": 8080, "tags": ["a", "b"], "labels": {"team": "core", "env": "prod"}}}`)
+
+	config, err := LoadConfig(system, filepath.Join(t.TempDir(), "missing.yaml"), user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{system, user}, config.Files(), "Missing files should be skipped")
+
+	tests := []struct {
+		key, value, source string
+	}{
+		{"serve.port", "8080", user},
+		{"serve.host", "example.com", system},
+		{"verbose", "true", system},
+		{"serve.tags", "a,b", user},
+		{"serve.labels", "env=prod,team=core", user},
+	}
+	for _, test := range tests {
+		value, source, exists := config.Lookup(test.key)
+		assert.True(t, exists, "Key %s should exist", test.key)
+		assert.Equal(t, test.value, value)
+		assert.Equal(t, test.source, source)
+	}
+	_, _, exists := config.Lookup("serve.port.number")
+	assert.False(t, exists)
+
+	_, err = LoadConfig(writeConfigFile(t, "invalid.yaml", "serve: [port"))
+	assert.IsType(t, &ConfigError{}, err)
+	assert.Equal(t, errors.ExitConfig, errors.ExitCode(err))
+}
+
+func TestConfigFallback(t *testing.T) {
+	writer := &mockOperator{}
+	portOpt := CommandOption{Label: "port", Letter: 'p', Name: "port", ValueType: TypeInt, Default: "80"}
+	hostOpt := CommandOption{Label: "host", Name: "host", ValueType: TypeString, ConfigKey: "server.host", Required: true}
+	debugOpt := CommandOption{Label: "debug", Name: "debug"}
+	serveHandler := func(input CommandInput, operator operator.Operator) errors.Error {
+		port, _ := input.ParseOption(portOpt)
+		host, _ := input.ParseOption(hostOpt)
+		debug, _ := input.ParseOption(debugOpt)
+		operator.Write(fmt.Sprintf("%v:%v %v", host, port, debug != nil))
+		return nil
+	}
+	serve := NewCommand("serve", "Serve the files.", serveHandler)
+	serve.AddOption(portOpt)
+	serve.AddOption(hostOpt)
+	commander := NewCommander().SetOperator(writer).SetEnvPrefix("MYCLI_")
+	commander.AddGlobalOption(ConfigFileOption)
+	commander.AddGlobalOption(debugOpt)
+	commander.AddCommand("serve", serve)
+	commander.AddCommand("config", ConfigCommand())
+	commander.SetConfigFiles(writeConfigFile(t, "config.yaml", "server:\n  host: example.com\nserve:\n  port: 8080\ndebug: true\n"))
+	local := writeConfigFile(t, "local.yaml", "serve:\n  port: 9000\n")
+
+	run := func(t *testing.T, input ...string) string {
+		writer.Reset()
+		err := commander.Run(input)
+		assert.NoError(t, err)
+		return writer.String()
+	}
+
+	t.Run("Config Files", func(t *testing.T) {
+		assert.Equal(t, "example.com:8080 true", run(t, "serve"))
+		assert.Equal(t, "example.com:9000 true", run(t, "--config", local, "serve"))
+	})
+
+	t.Run("Inherited By Nested Runs", func(t *testing.T) {
+		commander := NewCommander().SetOperator(writer)
+		commander.AddGlobalOption(ConfigFileOption)
+		commander.AddGlobalOption(debugOpt)
+		serve := NewCommand("serve", "Serve the files.", serveHandler)
+		serve.AddOption(portOpt)
+		serve.AddOption(CommandOption{Label: "host", Name: "host", ValueType: TypeString, ConfigKey: "server.host"})
+		commander.AddCommand("serve", serve)
+		commander.AddCommand("run", RunCommand())
+		run := func(t *testing.T, input ...string) string {
+			writer.Reset()
+			assert.NoError(t, commander.Run(input))
+			return writer.String()
+		}
+		script := writeConfigFile(t, "script.cli", "serve --host localhost\nserve --host localhost --config "+local+"\n")
+		assert.Equal(t, "localhost:80 false\nlocalhost:9000 false\n", run(t, "run", script))
+
+		other := writeConfigFile(t, "other.yaml", "serve:\n  port: 7000\n")
+		assert.Equal(t, "localhost:9000 true\nlocalhost:9000 true\n", run(t, "--config", local, "--debug", "run", script))
+		assert.Equal(t, "localhost:7000 true\nlocalhost:9000 true\n", run(t, "--config", other, "run", "--debug", script))
+		assert.Equal(t, []string{other}, commander.GetConfig().Files(), "The configuration of the outer command should be restored")
+	})
+
+	t.Run("Environment Takes Precedence", func(t *testing.T) {
+		t.Setenv("MYCLI_SERVE_PORT", "7000")
+		assert.Equal(t, "example.com:7000 true", run(t, "--config", local, "serve"))
+		assert.Equal(t, "example.com:6000 true", run(t, "serve", "-p", "6000"))
+	})
+
+	t.Run("Invalid Value", func(t *testing.T) {
+		invalid := writeConfigFile(t, "invalid.yaml", "serve:\n  port: http\n")
+		err := commander.Run([]string{"serve", "--config", invalid})
+		assert.IsType(t, &InvalidValueError{}, err)
+		assert.Contains(t, err.Display(), "for configuration key serve.port in "+invalid)
+	})
+
+	t.Run("Missing Config Option File", func(t *testing.T) {
+		err := commander.Run([]string{"serve", "--config", filepath.Join(t.TempDir(), "missing.yaml")})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+
+	t.Run("Config Command", func(t *testing.T) {
+		t.Setenv("MYCLI_SERVE_HOST", "localhost")
+		output := run(t, "config", "--config", local)
+		assert.Contains(t, output, "KEY")
+		assert.Regexp(t, `debug\s+true\s+.*config\.yaml`, output)
+		assert.Regexp(t, `serve\.port\s+9000\s+.*local\.yaml`, output)
+		assert.Regexp(t, `server\.host\s+localhost\s+env MYCLI_SERVE_HOST`, output)
+		assert.Regexp(t, `config\s+unset`, output)
+		assert.Contains(t, output, "Configuration files: ")
+	})
+}
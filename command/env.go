@@ -0,0 +1,106 @@
+package command
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+var envNameReplacer = strings.NewReplacer("-", "_", ".", "_", " ", "_")
+
+// EnvName returns the environment variable named after name under prefix,
+// e.g. MYCLI_DRY_RUN for dry-run or MYCLI_USER_ADD for "user add" under
+// MYCLI_.
+func EnvName(prefix string, name string) string {
+	return prefix + strings.ToUpper(envNameReplacer.Replace(name))
+}
+
+func (c *command) setEnvPrefix(prefix string) Command {
+	c.envPrefix = prefix
+	return c
+}
+
+// root returns the top level command of the tree the command belongs to.
+func (c *command) root() *command {
+	if parent, ok := c.parent.(*command); ok && parent != nil {
+		return parent.root()
+	}
+	return c
+}
+
+// withEnv returns opt with the environment variable it falls back to, named
+// after the env prefix of the command tree unless it has its own. Like config
+// keys, the names of command options are namespaced by the command path, so
+// that the port options of serve and db read MYCLI_SERVE_PORT and
+// MYCLI_DB_PORT.
+func (c *command) withEnv(opt CommandOption) CommandOption {
+	if prefix := c.root().envPrefix; opt.Env == "" && prefix != "" {
+		name := opt.Label
+		if !c.global {
+			name = c.Path() + " " + opt.Label
+		}
+		opt.Env = EnvName(prefix, name)
+	}
+	return opt
+}
+
+// lookupEnv returns the value of the environment variable name, unless it is
+// unset or empty.
+func lookupEnv(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	value, exists := os.LookupEnv(name)
+	return value, exists && value != ""
+}
+
+// setFromEnv sets the options that were not given from their environment
+// variable. Flags are set when it holds a true boolean.
+func (c *command) setFromEnv(inputOpts map[string]any) errors.Error {
+	for _, opt := range c.Options {
+		if _, given := inputOpts[opt.Label]; given {
+			continue
+		}
+		opt = c.withEnv(opt)
+		value, exists := lookupEnv(opt.Env)
+		if !exists {
+			continue
+		}
+		if opt.ValueType == NoType {
+			set, err := strconv.ParseBool(value)
+			if err != nil {
+				return newInvalidValueError(c, "environment variable", opt.Env, TypeBool, value, err)
+			}
+			if set {
+				inputOpts[opt.Label] = true
+			}
+			continue
+		}
+		if err := validateValue(opt.ValueType, opt.Choices, value); err != nil {
+			return newInvalidValueError(c, "environment variable", opt.Env, opt.ValueType, value, err)
+		}
+		inputOpts[opt.Label] = value
+	}
+	return nil
+}
+
+// argumentsWithEnv returns the arguments of the command in position order,
+// those whose environment variable is set being made optional and defaulting
+// to its value.
+func (c *command) argumentsWithEnv() ([]CommandArgument, errors.Error) {
+	arguments := c.sortedArguments()
+	for i, arg := range arguments {
+		value, exists := lookupEnv(arg.Env)
+		if !exists {
+			continue
+		}
+		if err := validateValue(arg.ValueType, arg.Choices, value); err != nil {
+			return nil, newInvalidValueError(c, "environment variable", arg.Env, arg.ValueType, value, err)
+		}
+		arguments[i].Optional = true
+		arguments[i].Default = value
+	}
+	return arguments, nil
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "MYCLI_DRY_RUN", EnvName("MYCLI_", "dry-run"))
+	assert.Equal(t, "PORT", EnvName("", "port"))
+	assert.Equal(t, "MYCLI_SERVE_RELOAD_PORT", EnvName("MYCLI_", "serve reload port"))
+}
+
+func TestEnvFallback(t *testing.T) {
+	writer := &mockOperator{}
+	commander := NewCommander().SetOperator(writer)
+	portOpt := CommandOption{Label: "port", Letter: 'p', Name: "port", ValueType: TypeInt, Default: "80", Description: "Port to listen on"}
+	hostOpt := CommandOption{Label: "host", Name: "host", ValueType: TypeString, Env: "SERVE_HOST", Required: true}
+	dryRunOpt := CommandOption{Label: "dry-run", Name: "dry-run"}
+	rootArg := CommandArgument{Label: "root", Position: 0, ValueType: TypeString, Env: "SERVE_ROOT", Description: "Directory to serve"}
+	serve := NewCommand("serve", "Serve the files.", func(input CommandInput, operator operator.Operator) errors.Error {
+		root, _ := input.ParseArgument(rootArg)
+		port, _ := input.ParseOption(portOpt)
+		host, _ := input.ParseOption(hostOpt)
+		dryRun, _ := input.ParseOption(dryRunOpt)
+		operator.Write(fmt.Sprintf("%v %v:%v %v", root, host, port, dryRun != nil))
+		return nil
+	})
+	serve.AddArgument(rootArg)
+	serve.AddOption(portOpt)
+	serve.AddOption(hostOpt)
+	serve.AddOption(dryRunOpt)
+	commander.AddCommand("serve", serve)
+	commander.SetEnvPrefix("MYCLI_")
+
+	run := func(t *testing.T, input ...string) (string, errors.Error) {
+		writer.Reset()
+		err := commander.Run(input)
+		return writer.String(), err
+	}
+
+	t.Run("Explicit Variable", func(t *testing.T) {
+		t.Setenv("SERVE_HOST", "localhost")
+		output, err := run(t, "serve", "/srv")
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv localhost:80 false", output, "The variable should satisfy a required option")
+	})
+
+	t.Run("Prefixed Variable", func(t *testing.T) {
+		t.Setenv("SERVE_HOST", "localhost")
+		t.Setenv("MYCLI_SERVE_PORT", "8080")
+		t.Setenv("MYCLI_SERVE_DRY_RUN", "true")
+		output, err := run(t, "serve", "/srv")
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv localhost:8080 true", output)
+	})
+
+	t.Run("Flags Take Precedence", func(t *testing.T) {
+		t.Setenv("SERVE_HOST", "localhost")
+		t.Setenv("MYCLI_SERVE_PORT", "8080")
+		output, err := run(t, "serve", "/srv", "-p", "9090", "--host", "example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv example.com:9090 false", output)
+	})
+
+	t.Run("Argument Variable", func(t *testing.T) {
+		t.Setenv("SERVE_HOST", "localhost")
+		t.Setenv("SERVE_ROOT", "/var/www")
+		output, err := run(t, "serve")
+		assert.NoError(t, err)
+		assert.Equal(t, "/var/www localhost:80 false", output)
+		output, err = run(t, "serve", "/srv")
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv localhost:80 false", output)
+	})
+
+	t.Run("Invalid Variable", func(t *testing.T) {
+		t.Setenv("SERVE_HOST", "localhost")
+		t.Setenv("MYCLI_SERVE_PORT", "http")
+		_, err := run(t, "serve", "/srv")
+		assert.IsType(t, &InvalidValueError{}, err)
+		assert.Equal(t, `Invalid value "http" for environment variable MYCLI_SERVE_PORT of command serve: expected int (invalid syntax)`, err.Display())
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		help := serve.Help()
+		assert.Contains(t, help, "Port to listen on (default: 80) (env: MYCLI_SERVE_PORT).")
+		assert.Contains(t, help, "\t   root <string>:  Directory to serve (env: SERVE_ROOT).\n")
+		assert.Contains(t, help, "(required) (env: SERVE_HOST).")
+	})
+
+	t.Run("Global Options", func(t *testing.T) {
+		commander.AddGlobalOption(CommandOption{Label: "verbose", Name: "verbose"})
+		assert.Equal(t, "MYCLI_VERBOSE", commander.GetGlobalOptions()[0].Env)
+	})
+
+	t.Run("Namespaced By Command Path", func(t *testing.T) {
+		reload := NewCommand("reload", "Reload the server.", func(input CommandInput, operator operator.Operator) errors.Error {
+			port, _ := input.ParseOption(portOpt)
+			operator.Write(fmt.Sprintf("%v", port))
+			return nil
+		})
+		reload.AddOption(portOpt)
+		serve.AddSubCommand(reload)
+		t.Setenv("SERVE_HOST", "localhost")
+		t.Setenv("MYCLI_PORT", "7000")
+		t.Setenv("MYCLI_SERVE_PORT", "8080")
+		t.Setenv("MYCLI_SERVE_RELOAD_PORT", "9090")
+		output, err := run(t, "serve", "/srv")
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv localhost:8080 false", output)
+		output, err = run(t, "serve", "reload")
+		assert.NoError(t, err)
+		assert.Equal(t, "9090", output)
+	})
+}
@@ -1,11 +1,17 @@
 package command
 
 import (
+	stderrors "errors"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
 )
 
 type InvalidCommandError struct {
-	command string
+	command     string
+	suggestions []string
 }
 
 func (e *InvalidCommandError) Error() string {
@@ -13,7 +19,28 @@ func (e *InvalidCommandError) Error() string {
 }
 
 func (e *InvalidCommandError) Display() string {
-	return fmt.Sprintf("Invalid command: %s", e.command)
+	return fmt.Sprintf("Invalid command: %s", e.command) + didYouMean(e.suggestions)
+}
+
+func (e *InvalidCommandError) ExitCode() int {
+	return errors.ExitUnknownCommand
+}
+
+type AmbiguousCommandError struct {
+	command    string
+	candidates []string
+}
+
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf("Ambiguous command: %s matches %s", e.command, strings.Join(e.candidates, ", "))
+}
+
+func (e *AmbiguousCommandError) Display() string {
+	return fmt.Sprintf("Ambiguous command: %s matches %s", e.command, strings.Join(e.candidates, ", "))
+}
+
+func (e *AmbiguousCommandError) ExitCode() int {
+	return errors.ExitUnknownCommand
 }
 
 type InvalidCommandUsageError struct {
@@ -21,17 +48,60 @@ type InvalidCommandUsageError struct {
 }
 
 func (e *InvalidCommandUsageError) Error() string {
-	return fmt.Sprintf("Invalid usage of command: %s", e.command.String())
+	return fmt.Sprintf("Invalid usage of command: %s", e.command.Path())
 }
 
 func (e *InvalidCommandUsageError) Display() string {
-	commandName := e.command.String()
+	commandName := e.command.Path()
 	return fmt.Sprintf("Invalid usage of command: %s\n\n> %s: %s\n", commandName, commandName, e.command.Help())
 }
 
+func (e *InvalidCommandUsageError) ExitCode() int {
+	return errors.ExitUsage
+}
+
+type InvalidValueError struct {
+	command string
+	kind    string
+	name    string
+	value   string
+	reason  string
+}
+
+// newInvalidValueError reports value as invalid for the argument or option
+// (kind) called name, explaining why from the error returned by its parser.
+func newInvalidValueError(command Command, kind string, name string, valueType ValueType, value string, err error) *InvalidValueError {
+	return &InvalidValueError{command: command.Path(), kind: kind, name: name, value: value, reason: invalidValueReason(valueType, err)}
+}
+
+// invalidValueReason explains why a value did not parse as valueType, naming
+// the expected type rather than the parsing function that failed.
+func invalidValueReason(valueType ValueType, err error) string {
+	var numErr *strconv.NumError
+	if stderrors.As(err, &numErr) {
+		return fmt.Sprintf("expected %s (%s)", TypeName(valueType), numErr.Err)
+	}
+	return err.Error()
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("Invalid value %q for %s %s of command %s: %s", e.value, e.kind, e.name, e.command, e.reason)
+}
+
+func (e *InvalidValueError) Display() string {
+	return fmt.Sprintf("Invalid value %q for %s %s of command %s: %s", e.value, e.kind, e.name, e.command, e.reason)
+}
+
+func (e *InvalidValueError) ExitCode() int {
+	return errors.ExitUsage
+}
+
 type UnreconizedFlagError struct {
 	command string
 	flag    string
+	// flags are those of the command, the suggestions are picked from.
+	flags       []string
+	suggestions []string
 }
 
 func (e *UnreconizedFlagError) Error() string {
@@ -39,7 +109,107 @@ func (e *UnreconizedFlagError) Error() string {
 }
 
 func (e *UnreconizedFlagError) Display() string {
-	return fmt.Sprintf("Unreconized flag %s for command %s", e.flag, e.command)
+	return fmt.Sprintf("Unreconized flag %s for command %s", e.flag, e.command) + didYouMean(e.suggestions)
+}
+
+func (e *UnreconizedFlagError) ExitCode() int {
+	return errors.ExitUsage
+}
+
+type MissingOptionError struct {
+	command string
+	option  string
+}
+
+func (e *MissingOptionError) Error() string {
+	return fmt.Sprintf("Missing required option %s for command %s", e.option, e.command)
+}
+
+func (e *MissingOptionError) Display() string {
+	return fmt.Sprintf("Missing required option %s for command %s", e.option, e.command)
+}
+
+func (e *MissingOptionError) ExitCode() int {
+	return errors.ExitUsage
+}
+
+type OptionGroupError struct {
+	command string
+	reason  string
+}
+
+func (e *OptionGroupError) Error() string {
+	return fmt.Sprintf("Invalid options for command %s: %s", e.command, e.reason)
+}
+
+func (e *OptionGroupError) Display() string {
+	return fmt.Sprintf("Invalid options for command %s: %s", e.command, e.reason)
+}
+
+func (e *OptionGroupError) ExitCode() int {
+	return errors.ExitUsage
+}
+
+type ConfigError struct {
+	path string
+	err  error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("Invalid configuration file %s: %s", e.path, e.err)
+}
+
+func (e *ConfigError) Display() string {
+	return fmt.Sprintf("Invalid configuration file %s: %s", e.path, e.err)
+}
+
+func (e *ConfigError) ExitCode() int {
+	return errors.ExitConfig
+}
+
+// Unwrap returns the error reading or decoding the file.
+func (e *ConfigError) Unwrap() error {
+	return e.err
+}
+
+type InterruptedError struct {
+	command string
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("Command %s interrupted", e.command)
+}
+
+func (e *InterruptedError) Display() string {
+	return fmt.Sprintf("Command %s interrupted", e.command)
+}
+
+func (e *InterruptedError) ExitCode() int {
+	return errors.ExitInterrupted
+}
+
+type ScriptError struct {
+	script string
+	line   int
+	err    errors.Error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.script, e.line, e.err.Error())
+}
+
+func (e *ScriptError) Display() string {
+	return fmt.Sprintf("%s:%d: %s", e.script, e.line, e.err.Display())
+}
+
+// ExitCode returns the exit code of the failing command.
+func (e *ScriptError) ExitCode() int {
+	return errors.ExitCode(e.err)
+}
+
+// Unwrap returns the error of the failing command.
+func (e *ScriptError) Unwrap() error {
+	return e.err
 }
 
 type CommandError struct {
@@ -57,3 +227,7 @@ func (e *CommandError) Error() string {
 func (e *CommandError) Display() string {
 	return e.message
 }
+
+func (e *CommandError) ExitCode() int {
+	return errors.ExitFailure
+}
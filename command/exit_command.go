@@ -1,21 +1,70 @@
 package command
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"strings"
 
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/cli/operator"
 )
 
-func exitHandler(_ CommandInput, _ operator.Operator) errors.Error {
-	os.Exit(0)
+// ShutdownHook releases a resource when the application exits. It should
+// return once ctx is done, which happens when the shutdown times out.
+type ShutdownHook func(ctx context.Context) error
+
+var exitStatusArg = CommandArgument{
+	Label:       "status",
+	Description: "Exit status of the application",
+	Position:    0,
+	ValueType:   TypeInt,
+	Optional:    true,
+	Default:     "0",
+}
+
+func exitHandler(input CommandInput, _ operator.Operator) errors.Error {
+	status, err := input.ParseArgument(exitStatusArg)
+	if err != nil {
+		return err
+	}
+	input.Commander().Exit(status.(int))
 	return nil
 }
 
 func ExitCommand() Command {
-	return NewCommand(
+	cmd := NewCommand(
 		"exit",
 		"Exit the application.",
 		exitHandler,
 	)
+	cmd.AddArgument(exitStatusArg)
+	return cmd
+}
+
+// Shutdown runs the shutdown hooks in the reverse order of their registration,
+// so resources are released before the ones they depend on, and forgets them.
+// Hooks still running when ctx is done are abandoned along with the remaining
+// ones.
+func (c *commander) Shutdown(ctx context.Context) errors.Error {
+	hooks := c.shutdownHooks
+	c.shutdownHooks = nil
+	var failures []string
+	for i := len(hooks) - 1; i >= 0; i-- {
+		done := make(chan error, 1)
+		go func() {
+			done <- hooks[i](ctx)
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				failures = append(failures, err.Error())
+			}
+		case <-ctx.Done():
+			return &CommandError{message: fmt.Sprintf("Shutdown timed out with %d hook(s) left to run", i+1)}
+		}
+	}
+	if len(failures) > 0 {
+		return &CommandError{message: "Shutdown failed: " + strings.Join(failures, "; ")}
+	}
+	return nil
 }
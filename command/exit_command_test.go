@@ -0,0 +1,104 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default Status", func(t *testing.T) {
+		commander := NewCommander().SetOperator(&mockOperator{})
+		commander.AddCommand("exit", ExitCommand())
+		err := commander.Run([]string{"exit"})
+		assert.NoError(t, err)
+		status, exiting := commander.ExitStatus()
+		assert.True(t, exiting, "The exit command should ask the application to exit")
+		assert.Equal(t, 0, status)
+	})
+
+	t.Run("Given Status", func(t *testing.T) {
+		commander := NewCommander().SetOperator(&mockOperator{})
+		commander.AddCommand("exit", ExitCommand())
+		err := commander.Run([]string{"exit", "3"})
+		assert.NoError(t, err)
+		status, _ := commander.ExitStatus()
+		assert.Equal(t, 3, status)
+	})
+
+	t.Run("Invalid Status", func(t *testing.T) {
+		commander := NewCommander().SetOperator(&mockOperator{})
+		commander.AddCommand("exit", ExitCommand())
+		err := commander.Run([]string{"exit", "now"})
+		assert.IsType(t, &InvalidValueError{}, err)
+		_, exiting := commander.ExitStatus()
+		assert.False(t, exiting)
+	})
+
+	t.Run("Script Stops On Exit", func(t *testing.T) {
+		writer := &mockOperator{}
+		commander := createScriptCommander(writer)
+		commander.AddCommand("exit", ExitCommand())
+		err := RunScript(context.Background(), commander, "test", strings.NewReader("echo one\nexit 4\necho two\n"), false)
+		assert.NoError(t, err)
+		assert.Equal(t, "one\n\n\n", writer.String())
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Reverse Order", func(t *testing.T) {
+		commander := NewCommander()
+		var order []int
+		for i := range 3 {
+			commander.AddShutdownHook(func(context.Context) error {
+				order = append(order, i)
+				return nil
+			})
+		}
+		assert.NoError(t, commander.Shutdown(context.Background()))
+		assert.Equal(t, []int{2, 1, 0}, order)
+		assert.NoError(t, commander.Shutdown(context.Background()), "Hooks should only run once")
+		assert.Len(t, order, 3)
+	})
+
+	t.Run("Failing Hooks", func(t *testing.T) {
+		commander := NewCommander()
+		ran := false
+		commander.AddShutdownHook(func(context.Context) error {
+			ran = true
+			return nil
+		})
+		commander.AddShutdownHook(func(context.Context) error {
+			return fmt.Errorf("database still busy")
+		})
+		err := commander.Shutdown(context.Background())
+		assert.True(t, ran, "A failing hook should not stop the ones registered before it")
+		assert.EqualError(t, err, "Shutdown failed: database still busy")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		commander := NewCommander()
+		ran := false
+		commander.AddShutdownHook(func(context.Context) error {
+			ran = true
+			return nil
+		})
+		commander.AddShutdownHook(func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := commander.Shutdown(ctx)
+		assert.EqualError(t, err, "Shutdown timed out with 2 hook(s) left to run")
+		assert.False(t, ran)
+	})
+}
@@ -2,23 +2,50 @@ package command
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/yassirdeveloper/cli/errors"
 	"github.com/yassirdeveloper/cli/operator"
 )
 
-var helpText string
-
 var commandOpt = CommandOption{
 	Label:  "command",
 	Letter: 'c', Name: "command",
 	ValueType:   TypeString,
-	Description: "Name of the command to get detailed help for",
+	Description: "Name of the command to get detailed help for, subcommands separated by spaces",
+}
+
+// commandHelp describes a command in the structured output of help.
+type commandHelp struct {
+	Command     string   `json:"command"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Description string   `json:"description"`
+	Usage       string   `json:"usage"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// describeCommand appends the description of cmd, then of its visible
+// subcommands, to help.
+func describeCommand(help []commandHelp, cmd Command) []commandHelp {
+	description := commandHelp{Command: cmd.Path(), Aliases: cmd.GetAliases(), Usage: cmd.Usage()}
+	if c, ok := cmd.(*command); ok {
+		description.Description = c.Description
+		for _, opt := range c.Options {
+			description.Options = append(description.Options, opt.flag())
+		}
+	}
+	help = append(help, description)
+	for _, name := range cmd.GetSubCommands() {
+		if sub, _ := cmd.GetSubCommand(name); !sub.IsHidden() {
+			help = describeCommand(help, sub)
+		}
+	}
+	return help
 }
 
 func helpHandler(input CommandInput, operator operator.Operator) errors.Error {
-	commander := GetCommander()
+	commander := input.Commander()
 
 	// If a specific command name is provided, show help for that command
 	opt, err := input.ParseOption(commandOpt)
@@ -27,25 +54,19 @@ func helpHandler(input CommandInput, operator operator.Operator) errors.Error {
 	}
 	if opt != nil {
 		cmdName := opt.(string)
-		cmd, exists := commander.Get(cmdName)
-		if exists {
-			err := operator.Write("Command description:\n" + cmd.Help())
-			if err != nil {
-				return errors.NewUnexpectedError(err)
-			}
-			return nil
+		cmd, rest, resolveErr := commander.Resolve(strings.Fields(cmdName))
+		if resolveErr == nil && len(rest) == 0 {
+			return WriteOutput(input, operator, TextOutput("Command description:\n"+cmd.Help(), describeCommand(nil, cmd)))
 		}
-		err := operator.Write(fmt.Sprintf("No help available for command: %s\n", cmdName))
-		if err != nil {
-			return errors.NewUnexpectedError(err)
-		}
-		return nil
+		return WriteOutput(input, operator, TextOutput(fmt.Sprintf("No help available for command: %s\n", cmdName), []commandHelp{}))
 	}
 
 	// Otherwise, list help for all commands
 	cmds := commander.GetCommands()
+	slices.Sort(cmds)
 	var description strings.Builder
-	if helpText != "" {
+	var help []commandHelp
+	if helpText := commander.GetHelpText(); helpText != "" {
 		description.WriteString(helpText)
 	}
 	description.WriteString("List of commands:\n")
@@ -54,22 +75,27 @@ func helpHandler(input CommandInput, operator operator.Operator) errors.Error {
 		if !exists {
 			panic(fmt.Sprintf("Commander does not return a Command for an existing command name %s", cmd))
 		}
+		if comm.IsHidden() {
+			continue
+		}
 		description.WriteString(comm.Help())
+		help = describeCommand(help, comm)
 	}
-	err_ := operator.Write(description.String())
-	if err_ != nil {
-		return errors.NewUnexpectedError(err_)
+	if globals := commander.GetGlobalOptions(); len(globals) > 0 {
+		description.WriteString("Global options:\n")
+		for _, opt := range globals {
+			description.WriteString(opt.help())
+		}
 	}
-	return nil
+	return WriteOutput(input, operator, TextOutput(description.String(), help))
 }
 
-func HelpCommand(s string) Command {
+func HelpCommand() Command {
 	cmd := NewCommand(
 		"help",
 		"Display help information for commands.",
 		helpHandler,
 	)
-	helpText = s
 	cmd.AddOption(commandOpt)
 	return cmd
 }
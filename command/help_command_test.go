@@ -8,9 +8,10 @@ import (
 )
 
 func TestHelpCommand(t *testing.T) {
+	t.Parallel()
 	exitCmd := createExitCommand()
-	versionCmd := createVersionCommand("0.0.0")
-	commander := GetCommander()
+	versionCmd := createVersionCommand()
+	commander := NewCommander()
 	commander.AddCommand("exit", exitCmd)
 	commander.AddCommand("version", versionCmd)
 
@@ -22,6 +23,7 @@ func TestHelpCommand(t *testing.T) {
 		input := &commandInput{
 			arguments: map[string]any{},
 			options:   map[string]any{},
+			commander: commander,
 		}
 
 		err := helpCommand.Handle(input, writer)
@@ -29,6 +31,21 @@ func TestHelpCommand(t *testing.T) {
 		assert.NotEmpty(t, writer.String())
 	})
 
+	t.Run("Help Text From Commander", func(t *testing.T) {
+		writer.Reset()
+		commander.SetHelpText("A test application.\n")
+
+		input := &commandInput{
+			arguments: map[string]any{},
+			options:   map[string]any{},
+			commander: commander,
+		}
+
+		err := helpCommand.Handle(input, writer)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(writer.String(), "A test application.\n"))
+	})
+
 	t.Run("Get Help for Specific Command", func(t *testing.T) {
 		writer.Reset()
 
@@ -37,6 +54,7 @@ func TestHelpCommand(t *testing.T) {
 			options: map[string]any{
 				"command": "exit",
 			},
+			commander: commander,
 		}
 
 		err := helpCommand.Handle(input, writer)
@@ -52,6 +70,7 @@ func TestHelpCommand(t *testing.T) {
 			options: map[string]any{
 				"command": "nonexistent",
 			},
+			commander: commander,
 		}
 
 		err := helpCommand.Handle(input, writer)
@@ -60,4 +79,21 @@ func TestHelpCommand(t *testing.T) {
 		expectedOutput := "No help available for command: nonexistent\n"
 		assert.Equal(t, expectedOutput, writer.String())
 	})
+
+	t.Run("Structured Output", func(t *testing.T) {
+		writer.Reset()
+
+		input := &commandInput{
+			arguments: map[string]any{},
+			options: map[string]any{
+				"command": "exit",
+				"output":  OutputCSV,
+			},
+			commander: commander,
+		}
+
+		err := helpCommand.Handle(input, writer)
+		assert.NoError(t, err)
+		assert.Equal(t, "command,aliases,description,usage,options\nexit,,Exit the application.,exit [status],", writer.String())
+	})
 }
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// History gives commands access to the entries of the interactive shell
+// history, oldest first.
+type History interface {
+	Entries() []string
+	Clear() error
+}
+
+var lastOpt = CommandOption{
+	Label:       "last",
+	Letter:      'n',
+	Name:        "last",
+	ValueType:   TypeInt,
+	Description: "Number of most recent entries to show",
+}
+
+var termArg = CommandArgument{
+	Label:       "term",
+	Description: "Text to search the entries for",
+	Position:    0,
+	ValueType:   TypeString,
+}
+
+func getHistory(input CommandInput) (History, errors.Error) {
+	history := input.Commander().GetHistory()
+	if history == nil {
+		return nil, &CommandError{message: "History is not available outside of the interactive shell"}
+	}
+	return history, nil
+}
+
+// writeHistoryEntries writes the entries from index from on that keep accepts,
+// numbered after their position in the whole history.
+func writeHistoryEntries(operator operator.Operator, entries []string, from int, keep func(string) bool) errors.Error {
+	output := &strings.Builder{}
+	for i := from; i < len(entries); i++ {
+		if keep(entries[i]) {
+			fmt.Fprintf(output, "%5d  %s\n", i+1, entries[i])
+		}
+	}
+	err := operator.Write(output.String())
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func historyListHandler(input CommandInput, operator operator.Operator) errors.Error {
+	history, err := getHistory(input)
+	if err != nil {
+		return err
+	}
+	last, err := input.ParseOption(lastOpt)
+	if err != nil {
+		return err
+	}
+	entries := history.Entries()
+	from := 0
+	if last != nil {
+		from = max(len(entries)-last.(int), 0)
+	}
+	return writeHistoryEntries(operator, entries, from, func(string) bool { return true })
+}
+
+func historySearchHandler(input CommandInput, operator operator.Operator) errors.Error {
+	history, err := getHistory(input)
+	if err != nil {
+		return err
+	}
+	term, err := input.ParseArgument(termArg)
+	if err != nil {
+		return err
+	}
+	return writeHistoryEntries(operator, history.Entries(), 0, func(entry string) bool {
+		return strings.Contains(entry, term.(string))
+	})
+}
+
+func historyClearHandler(input CommandInput, operator operator.Operator) errors.Error {
+	history, err := getHistory(input)
+	if err != nil {
+		return err
+	}
+	if err := history.Clear(); err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	return nil
+}
+
+func HistoryCommand() Command {
+	cmd := NewCommand(
+		"history",
+		"List, search or clear the history of the interactive shell.",
+		historyListHandler,
+	)
+	cmd.AddOption(lastOpt)
+
+	list := NewCommand("list", "List the history entries.", historyListHandler)
+	list.AddOption(lastOpt)
+	cmd.AddSubCommand(list)
+
+	search := NewCommand("search", "List the history entries containing a term.", historySearchHandler)
+	search.AddArgument(termArg)
+	cmd.AddSubCommand(search)
+
+	cmd.AddSubCommand(NewCommand("clear", "Clear the history entries.", historyClearHandler))
+	return cmd
+}
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHistory struct {
+	entries []string
+}
+
+func (h *mockHistory) Entries() []string {
+	return h.entries
+}
+
+func (h *mockHistory) Clear() error {
+	h.entries = nil
+	return nil
+}
+
+func TestHistoryCommand(t *testing.T) {
+	t.Parallel()
+	writer := &mockOperator{}
+	history := &mockHistory{entries: []string{"version", "help -c exit", "greet", "help"}}
+	commander := NewCommander().SetOperator(writer)
+	commander.AddCommand("history", HistoryCommand())
+
+	t.Run("Unavailable", func(t *testing.T) {
+		err := commander.Run([]string{"history"})
+		assert.IsType(t, &CommandError{}, err)
+	})
+
+	commander.SetHistory(history)
+
+	t.Run("List", func(t *testing.T) {
+		writer.Reset()
+		assert.NoError(t, commander.Run([]string{"history"}))
+		assert.Equal(t, "    1  version\n    2  help -c exit\n    3  greet\n    4  help\n", writer.String())
+	})
+
+	t.Run("List Last Entries", func(t *testing.T) {
+		writer.Reset()
+		assert.NoError(t, commander.Run([]string{"history", "list", "-n", "2"}))
+		assert.Equal(t, "    3  greet\n    4  help\n", writer.String())
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		writer.Reset()
+		assert.NoError(t, commander.Run([]string{"history", "search", "help"}))
+		assert.Equal(t, "    2  help -c exit\n    4  help\n", writer.String())
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		assert.NoError(t, commander.Run([]string{"history", "clear"}))
+		assert.Empty(t, history.Entries())
+	})
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// InteractiveOptionLabel is the label of InteractiveOption.
+const InteractiveOptionLabel = "interactive"
+
+// InteractiveOption is a global option making the commands prompt for their
+// missing arguments rather than failing.
+var InteractiveOption = CommandOption{
+	Label:       InteractiveOptionLabel,
+	Name:        "interactive",
+	ValueType:   NoType,
+	Description: "Prompt for the missing arguments",
+}
+
+// argumentPrompter asks for the value of an argument missing from the input.
+type argumentPrompter func(CommandArgument) (string, errors.Error)
+
+// promptArguments asks for the required arguments left without a value once
+// the given positional tokens are assigned, in position order.
+func (c *command) promptArguments(arguments []CommandArgument, given int, prompt argumentPrompter) ([]string, errors.Error) {
+	var answers []string
+	for _, arg := range arguments {
+		if arg.Optional {
+			continue
+		}
+		if given > 0 {
+			given--
+			continue
+		}
+		answer, err := prompt(arg)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+// promptArgument asks for arguments through the operator of the commander.
+// The answer is read to its end even when ctx is canceled meanwhile, so that
+// nothing is left reading the operator, then dropped.
+func (c *commander) promptArgument(ctx context.Context, path string) argumentPrompter {
+	return func(arg CommandArgument) (string, errors.Error) {
+		value, err := askArgument(c.operator, arg)
+		if ctx.Err() != nil {
+			return "", &InterruptedError{command: path}
+		}
+		return value, err
+	}
+}
+
+// askArgument asks for the value of arg, to pick among its choices for enums,
+// until it parses as its value type.
+func askArgument(op operator.Operator, arg CommandArgument) (string, errors.Error) {
+	prompt := argumentPrompt(arg)
+	if arg.ValueType == TypeEnum {
+		return operator.Select(op, prompt, arg.Choices, "")
+	}
+	return operator.Input(op, prompt, func(value string) error {
+		if value == "" {
+			return stderrors.New("a value is required")
+		}
+		if _, err := ParseValue(arg.ValueType, value); err != nil {
+			return stderrors.New(invalidValueReason(arg.ValueType, err))
+		}
+		return nil
+	})
+}
+
+// argumentPrompt describes the argument asked for, e.g. "Name of the user
+// (name <string>)".
+func argumentPrompt(arg CommandArgument) string {
+	prompt := arg.Label
+	if typeName := TypeName(arg.ValueType); typeName != "" {
+		prompt += " <" + typeName + ">"
+	}
+	if hint := typeHint(arg.ValueType); hint != "" {
+		prompt += ", e.g. " + hint
+	}
+	if arg.Description == "" {
+		return prompt
+	}
+	return arg.Description + " (" + prompt + ")"
+}
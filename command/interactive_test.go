@@ -0,0 +1,63 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func createInteractiveCommander(op operator.Operator) Commander {
+	commander := NewCommander().SetOperator(op)
+	commander.AddGlobalOption(InteractiveOption)
+	commander.AddCommand("deploy", NewCommand("deploy", "Deploy the application.", func(input CommandInput, operator operator.Operator) errors.Error {
+		name, _ := input.ParseArgument(CommandArgument{Label: "name", ValueType: TypeString})
+		replicas, _ := input.ParseArgument(CommandArgument{Label: "replicas", ValueType: TypeInt})
+		target, _ := input.ParseArgument(CommandArgument{Label: "target", ValueType: TypeEnum})
+		return operator.Write(fmt.Sprintf("deploying %d replicas of %s to %s\n", replicas, name, target))
+	}))
+	deploy, _ := commander.Get("deploy")
+	deploy.AddArgument(CommandArgument{Label: "name", Description: "Name of the release", Position: 0, ValueType: TypeString})
+	deploy.AddArgument(CommandArgument{Label: "replicas", Position: 1, ValueType: TypeInt})
+	deploy.AddArgument(CommandArgument{Label: "target", Position: 2, ValueType: TypeEnum, Choices: []string{"dev", "prod"}})
+	deploy.AddArgument(CommandArgument{Label: "note", Position: 3, ValueType: TypeString, Optional: true})
+	return commander
+}
+
+func TestPromptMissingArguments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Not Interactive", func(t *testing.T) {
+		err := createInteractiveCommander(operator.NewScriptedOperator()).Run([]string{"deploy", "api"})
+		assert.IsType(t, &InvalidCommandUsageError{}, err)
+	})
+
+	t.Run("Interactive Commander", func(t *testing.T) {
+		op := operator.NewScriptedOperator("many", "3", "2")
+		commander := createInteractiveCommander(op).SetInteractive(true)
+
+		assert.NoError(t, commander.Run([]string{"deploy", "api"}))
+		assert.Equal(t, "replicas <int>: many\n"+
+			"Invalid answer: expected int (invalid syntax)\n"+
+			"replicas <int>: 3\n"+
+			"target <enum>:\n  1) dev\n  2) prod\nChoice: 2\n"+
+			"deploying 3 replicas of api to prod\n", op.Output())
+	})
+
+	t.Run("Interactive Option", func(t *testing.T) {
+		op := operator.NewScriptedOperator("", "api", "1", "dev")
+		commander := createInteractiveCommander(op)
+
+		assert.NoError(t, commander.Run([]string{"deploy", "--interactive"}))
+		assert.Contains(t, op.Output(), "Name of the release (name <string>): \nInvalid answer: a value is required\n")
+		assert.Contains(t, op.Output(), "deploying 1 replicas of api to dev\n")
+	})
+
+	t.Run("End Of Input", func(t *testing.T) {
+		commander := createInteractiveCommander(operator.NewScriptedOperator("api"))
+		err := commander.Run([]string{"deploy", "--interactive"})
+		assert.IsType(t, &operator.PromptError{}, err)
+	})
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// OptionGroupKind tells how many options of a group may be given together.
+type OptionGroupKind int
+
+const (
+	// GroupExactlyOne groups require one of their options, and only one.
+	GroupExactlyOne OptionGroupKind = iota
+	// GroupAtMostOne groups accept any one of their options, or none.
+	GroupAtMostOne
+	// GroupTogether groups require all of their options once one is given.
+	GroupTogether
+)
+
+func (k OptionGroupKind) String() string {
+	switch k {
+	case GroupExactlyOne:
+		return "Exactly one of"
+	case GroupAtMostOne:
+		return "At most one of"
+	case GroupTogether:
+		return "All or none of"
+	}
+	return ""
+}
+
+// OptionGroup constrains how the options with the given labels can be used
+// together.
+type OptionGroup struct {
+	Kind   OptionGroupKind
+	Labels []string
+}
+
+// AddOptionGroup constrains the options of the command with the given labels,
+// which need to be added beforehand, to be used following kind.
+func (c *command) AddOptionGroup(kind OptionGroupKind, labels ...string) (Command, errors.Error) {
+	if len(labels) < 2 {
+		return nil, errors.NewSetupError(fmt.Sprintf("Option group of command %s needs at least 2 options!", c.Name))
+	}
+	for _, label := range labels {
+		opt, exists := c.findOption(label)
+		if !exists {
+			return nil, errors.NewSetupError(fmt.Sprintf("Option group of command %s refers to unknown option %s!", c.Name, label))
+		}
+		if opt.Default != "" {
+			return nil, errors.NewSetupError(fmt.Sprintf("Option %s of command %s has a default value and cannot be grouped!", label, c.Name))
+		}
+	}
+	c.OptionGroups = append(c.OptionGroups, OptionGroup{Kind: kind, Labels: labels})
+	return c, nil
+}
+
+func (c *command) findOption(label string) (CommandOption, bool) {
+	for _, opt := range c.Options {
+		if opt.Label == label {
+			return opt, true
+		}
+	}
+	return CommandOption{}, false
+}
+
+// validateOptions checks that the required options were given and that the
+// option groups are respected, before default values are set.
+func (c *command) validateOptions(inputOpts map[string]any) errors.Error {
+	for _, opt := range c.Options {
+		if _, given := inputOpts[opt.Label]; opt.Required && !given {
+			return &MissingOptionError{command: c.Path(), option: opt.flag()}
+		}
+	}
+	for _, group := range c.OptionGroups {
+		var flags, given, missing []string
+		for _, label := range group.Labels {
+			opt, _ := c.findOption(label)
+			flags = append(flags, opt.flag())
+			if _, exists := inputOpts[label]; exists {
+				given = append(given, opt.flag())
+			} else {
+				missing = append(missing, opt.flag())
+			}
+		}
+		switch {
+		case group.Kind == GroupExactlyOne && len(given) == 0:
+			return &OptionGroupError{command: c.Path(), reason: fmt.Sprintf("one of %s is required", strings.Join(flags, ", "))}
+		case group.Kind != GroupTogether && len(given) > 1:
+			return &OptionGroupError{command: c.Path(), reason: fmt.Sprintf("%s cannot be used together", strings.Join(given, " and "))}
+		case group.Kind == GroupTogether && len(given) > 0 && len(missing) > 0:
+			return &OptionGroupError{command: c.Path(), reason: fmt.Sprintf("%s must be used with %s", strings.Join(given, " and "), strings.Join(missing, " and "))}
+		}
+	}
+	return nil
+}
+
+// groupsHelp renders the option groups as listed under the command in help.
+func (c *command) groupsHelp() string {
+	var help strings.Builder
+	for _, group := range c.OptionGroups {
+		flags := make([]string, 0, len(group.Labels))
+		for _, label := range group.Labels {
+			if opt, exists := c.findOption(label); exists && !slices.Contains(flags, opt.flag()) {
+				flags = append(flags, opt.flag())
+			}
+		}
+		help.WriteString(fmt.Sprintf("\t   %s: %s.\n", group.Kind, strings.Join(flags, ", ")))
+	}
+	return help.String()
+}
@@ -0,0 +1,321 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats handlers can render their values in with WriteOutput.
+const (
+	// OutputText renders values as their String method, if they have one,
+	// else like OutputTable.
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+	OutputCSV   = "csv"
+)
+
+// OutputOptionLabel labels the global option choosing the output format.
+const OutputOptionLabel = "output"
+
+// OutputOption is the global option choosing the format WriteOutput renders
+// values in.
+var OutputOption = CommandOption{
+	Label:       OutputOptionLabel,
+	Name:        "output",
+	ValueType:   TypeEnum,
+	Choices:     []string{OutputText, OutputJSON, OutputYAML, OutputTable, OutputCSV},
+	Default:     OutputText,
+	Description: "Format of the output",
+}
+
+// OutputFormat returns the output format chosen with the output global
+// option, OutputText if none, or when the command has an output option of its
+// own.
+func OutputFormat(input CommandInput) string {
+	format, _ := input.ParseOption(OutputOption)
+	if format, ok := format.(string); ok && slices.Contains(OutputOption.Choices, format) {
+		return format
+	}
+	return OutputText
+}
+
+// WriteOutput renders value in the output format chosen for the command and
+// writes it, so the same handler serves humans and scripts. Tables and CSV
+// have a row per item of slices, a column per field of structs, tagged with
+// `json` to rename them, and per key of maps.
+//
+// Tables are rendered for the operator, as tab separated values when it is not
+// a terminal and truncated to its width otherwise.
+func WriteOutput(input CommandInput, operator operator.Operator, value any) errors.Error {
+	format := OutputFormat(input)
+	if table, ok := outputTable(format, value); ok {
+		return operator.Write(strings.TrimSuffix(table.RenderString(operator), "\n"))
+	}
+	output, err := RenderOutput(format, value)
+	if err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	if err := operator.Write(output); err != nil {
+		return err
+	}
+	return nil
+}
+
+// outputTable returns the table value is laid out as in format, unless it is
+// not rendered as a table in that format.
+func outputTable(format string, value any) (*operator.Table, bool) {
+	switch format {
+	case OutputText:
+		if _, ok := value.(fmt.Stringer); ok {
+			return nil, false
+		}
+	case OutputTable:
+		if output, ok := value.(textOutput); ok {
+			value = output.value
+		}
+	default:
+		return nil, false
+	}
+	return newTable(value), true
+}
+
+type textOutput struct {
+	text  string
+	value any
+}
+
+func (o textOutput) String() string {
+	return o.text
+}
+
+// TextOutput pairs value with the text it is rendered as in OutputText, for
+// values whose human rendering differs from their data.
+func TextOutput(text string, value any) any {
+	return textOutput{text: text, value: value}
+}
+
+// RenderOutput renders value in format, one of the Output constants.
+func RenderOutput(format string, value any) (string, error) {
+	if format == OutputText {
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return stringer.String(), nil
+		}
+	}
+	if output, ok := value.(textOutput); ok {
+		value = output.value
+	}
+	switch format {
+	case OutputText:
+		return renderTable(value), nil
+	case OutputJSON:
+		output, err := json.MarshalIndent(value, "", "  ")
+		return string(output), err
+	case OutputYAML:
+		// Go through JSON so fields are named the same in both formats
+		var generic any
+		if err := jsonRoundTrip(value, &generic); err != nil {
+			return "", err
+		}
+		output, err := yaml.Marshal(generic)
+		return strings.TrimSuffix(string(output), "\n"), err
+	case OutputTable:
+		return renderTable(value), nil
+	case OutputCSV:
+		return renderCSV(value)
+	}
+	return "", fmt.Errorf("unsupported output format %s", format)
+}
+
+func jsonRoundTrip(value any, target any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, target)
+}
+
+func renderTable(value any) string {
+	return strings.TrimSuffix(newTable(value).String(), "\n")
+}
+
+// newTable lays value out in a table, with a header per column.
+func newTable(value any) *operator.Table {
+	header, rows := tabulate(value)
+	table := operator.NewTable(upper(header)...)
+	for _, row := range rows {
+		table.AddRow(row...)
+	}
+	return table
+}
+
+func renderCSV(value any) (string, error) {
+	header, rows := tabulate(value)
+	builder := &strings.Builder{}
+	output := csv.NewWriter(builder)
+	if err := output.Write(header); err != nil {
+		return "", err
+	}
+	if err := output.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+func upper(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, strings.ToUpper(value))
+	}
+	return result
+}
+
+// tabulate lays value out as rows: a row per item of slices and arrays, a
+// single row otherwise, with a column per field of structs, per key of maps,
+// or a single value column for anything else.
+func tabulate(value any) ([]string, [][]string) {
+	items := []reflect.Value{}
+	v := indirect(reflect.ValueOf(value))
+	if v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, indirect(v.Index(i)))
+		}
+	} else if v.IsValid() {
+		items = append(items, v)
+	}
+
+	var header []string
+	for _, item := range items {
+		for _, column := range columns(item) {
+			if !slices.Contains(header, column) {
+				header = append(header, column)
+			}
+		}
+	}
+	if len(header) == 0 {
+		header = []string{"value"}
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, 0, len(header))
+		for _, column := range header {
+			row = append(row, cell(field(item, column)))
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldName returns the column name of a struct field, after its json tag,
+// and whether it is shown.
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+func columns(item reflect.Value) []string {
+	var result []string
+	switch item.Kind() {
+	case reflect.Struct:
+		if _, isTime := item.Interface().(time.Time); isTime {
+			return nil
+		}
+		for _, f := range reflect.VisibleFields(item.Type()) {
+			if name, shown := fieldName(f); shown && !f.Anonymous {
+				result = append(result, name)
+			}
+		}
+	case reflect.Map:
+		for _, key := range item.MapKeys() {
+			result = append(result, fmt.Sprint(key.Interface()))
+		}
+		slices.Sort(result)
+	}
+	return result
+}
+
+func field(item reflect.Value, column string) reflect.Value {
+	switch item.Kind() {
+	case reflect.Struct:
+		if len(columns(item)) == 0 {
+			break
+		}
+		for _, f := range reflect.VisibleFields(item.Type()) {
+			if name, shown := fieldName(f); shown && !f.Anonymous && name == column {
+				return item.FieldByIndex(f.Index)
+			}
+		}
+		return reflect.Value{}
+	case reflect.Map:
+		for _, key := range item.MapKeys() {
+			if fmt.Sprint(key.Interface()) == column {
+				return item.MapIndex(key)
+			}
+		}
+		return reflect.Value{}
+	}
+	if column == "value" {
+		return item
+	}
+	return reflect.Value{}
+}
+
+// cell formats a value to fit in a table or CSV cell.
+func cell(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, cell(v.Index(i)))
+		}
+		return strings.Join(items, ", ")
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, key := range v.MapKeys() {
+			keys[fmt.Sprint(key.Interface())] = v.MapIndex(key)
+		}
+		pairs := make([]string, 0, len(keys))
+		for _, key := range slices.Sorted(maps.Keys(keys)) {
+			pairs = append(pairs, key+"="+cell(keys[key]))
+		}
+		return strings.Join(pairs, ", ")
+	}
+	return fmt.Sprint(v.Interface())
+}
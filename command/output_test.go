@@ -0,0 +1,95 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type server struct {
+	Name    string            `json:"name"`
+	Port    int               `json:"port"`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Started time.Time         `json:"started"`
+	secret  string
+	Ignored string `json:"-"`
+}
+
+func TestRenderOutput(t *testing.T) {
+	t.Parallel()
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	servers := []server{
+		{Name: "api", Port: 8080, Tags: []string{"a", "b"}, Labels: map[string]string{"team": "core"}, Started: started, secret: "x", Ignored: "y"},
+		{Name: "web, public", Port: 80, Started: started},
+	}
+
+	tests := []struct {
+		format   string
+		value    any
+		expected string
+	}{
+		{OutputJSON, servers[1:], "[\n  {\n    \"name\": \"web, public\",\n    \"port\": 80,\n    \"started\": \"2024-05-01T12:00:00Z\"\n  }\n]"},
+		{OutputYAML, servers[1:], "- name: web, public\n  port: 80\n  started: \"2024-05-01T12:00:00Z\""},
+		{OutputTable, servers, "NAME         PORT  TAGS  LABELS     STARTED\napi          8080  a, b  team=core  2024-05-01T12:00:00Z\nweb, public  80                     2024-05-01T12:00:00Z"},
+		{OutputCSV, servers, "name,port,tags,labels,started\napi,8080,\"a, b\",team=core,2024-05-01T12:00:00Z\n\"web, public\",80,,,2024-05-01T12:00:00Z"},
+		{OutputText, &servers[0], "NAME  PORT  TAGS  LABELS     STARTED\napi   8080  a, b  team=core  2024-05-01T12:00:00Z"},
+		{OutputTable, map[string]int{"b": 2, "a": 1}, "A  B\n1  2"},
+		{OutputCSV, []string{"x", "y"}, "value\nx\ny"},
+		{OutputTable, 42, "VALUE\n42"},
+		{OutputText, TextOutput("v1.0.0", map[string]string{"version": "1.0.0"}), "v1.0.0"},
+		{OutputJSON, TextOutput("v1.0.0", map[string]string{"version": "1.0.0"}), "{\n  \"version\": \"1.0.0\"\n}"},
+	}
+	for _, test := range tests {
+		output, err := RenderOutput(test.format, test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, output, "Unexpected %s output", test.format)
+	}
+
+	_, err := RenderOutput("xml", servers)
+	assert.Error(t, err)
+}
+
+func TestWriteOutput(t *testing.T) {
+	t.Parallel()
+	writer := &mockOperator{}
+	commander := NewCommander().SetOperator(writer).SetName("app").SetVersion("1.2.3")
+	commander.AddCommand("version", VersionCommand())
+
+	assert.NoError(t, commander.Run([]string{"version"}))
+	assert.Equal(t, "v1.2.3", writer.String(), "Commanders without the output option should render text")
+
+	_, err := commander.AddGlobalOption(OutputOption)
+	assert.NoError(t, err)
+	writer.Reset()
+	assert.NoError(t, commander.Run([]string{"version", "--output", "json"}))
+	assert.Equal(t, "{\n  \"name\": \"app\",\n  \"version\": \"1.2.3\"\n}", writer.String())
+	writer.Reset()
+	assert.NoError(t, commander.Run([]string{"--output=csv", "version"}))
+	assert.Equal(t, "name,version\napp,1.2.3", writer.String())
+
+	writer.Reset()
+	assert.NoError(t, commander.Run([]string{"version", "--output", "table"}))
+	assert.Equal(t, "NAME\tVERSION\napp\t1.2.3", writer.String(), "Tables should be tab separated when not written to a terminal")
+
+	terminal := &terminalOperator{width: 12}
+	commander.SetOperator(terminal)
+	assert.NoError(t, commander.Run([]string{"version", "--output", "table"}))
+	assert.Equal(t, "NAME  VERSI…\napp   1.2.3", terminal.String(), "Tables should fit the width of the terminal")
+}
+
+// terminalOperator is a mockOperator standing for a terminal of the given
+// width.
+type terminalOperator struct {
+	mockOperator
+	width int
+}
+
+func (o *terminalOperator) IsTerminal() bool {
+	return true
+}
+
+func (o *terminalOperator) Width() int {
+	return o.width
+}
@@ -0,0 +1,335 @@
+package command
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// OptionsTerminator ends option parsing, every token after it is taken as a
+// positional argument.
+const OptionsTerminator = "--"
+
+// Parse tokenizes the input following GNU conventions: long options are given
+// as "--name value" or "--name=value", short ones as "-x value" or "-xvalue",
+// short flags can be bundled ("-abc") and "--" marks the end of the options.
+// Options and positional arguments may be interleaved.
+func (c *command) Parse(input []string) (CommandInput, errors.Error) {
+	inputOpts, _, positionals, err := c.parseOptions(input, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseInput(inputOpts, positionals, nil)
+}
+
+// parseOptions takes the options out of input in a single pass, those of the
+// command along with those of globals, the global options it does not shadow.
+// A value is thus always taken by the option before it, whether it looks like
+// a flag or not, and short flags of both can be bundled. It returns the values
+// of the options of the command, those of the global options, and the
+// positional tokens.
+func (c *command) parseOptions(input []string, globals *command) (map[string]any, map[string]any, []string, errors.Error) {
+	withGlobals := c
+	if globals != nil {
+		withGlobals = c.withOptions(globals.Options)
+	}
+	inputOpts, positionals, err := withGlobals.scanOptions(input)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	globalOpts := make(map[string]any)
+	if globals != nil {
+		for _, opt := range globals.Options {
+			if value, given := inputOpts[opt.Label]; given {
+				globalOpts[opt.Label] = value
+				delete(inputOpts, opt.Label)
+			}
+		}
+	}
+	return inputOpts, globalOpts, positionals, nil
+}
+
+// withOptions returns a copy of the command also accepting opts, after its
+// own options.
+func (c *command) withOptions(opts []CommandOption) *command {
+	withOpts := *c
+	withOpts.Options = append(slices.Clone(c.Options), opts...)
+	return &withOpts
+}
+
+// scanOptions returns the values of the options of the command given in
+// input, and the positional tokens left.
+func (c *command) scanOptions(input []string) (map[string]any, []string, errors.Error) {
+	inputOpts := make(map[string]any)
+	var positionals []string
+
+	for i := 0; i < len(input); i++ {
+		token := input[i]
+		switch {
+		case token == OptionsTerminator:
+			positionals = append(positionals, input[i+1:]...)
+			i = len(input)
+		case strings.HasPrefix(token, OptionNamePrefix):
+			name, value, hasValue := strings.Cut(strings.TrimPrefix(token, OptionNamePrefix), "=")
+			opt, exists := c.findOptionByName(name)
+			if !exists {
+				return nil, nil, &UnreconizedFlagError{command: c.Name, flag: OptionNamePrefix + name, flags: c.flags()}
+			}
+			if opt.ValueType == NoType {
+				if hasValue {
+					return nil, nil, &InvalidCommandUsageError{command: c}
+				}
+				inputOpts[opt.Label] = true
+				continue
+			}
+			if !hasValue {
+				if i+1 >= len(input) {
+					return nil, nil, &InvalidCommandUsageError{command: c}
+				}
+				i++
+				value = input[i]
+			}
+			if err := c.setOption(inputOpts, opt, value); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(token, OptionLetterPrefix) && token != OptionLetterPrefix && !c.isNegativeNumber(token):
+			letters := []rune(strings.TrimPrefix(token, OptionLetterPrefix))
+			for j := 0; j < len(letters); j++ {
+				opt, exists := c.findOptionByLetter(letters[j])
+				if !exists {
+					return nil, nil, &UnreconizedFlagError{command: c.Name, flag: OptionLetterPrefix + string(letters[j]), flags: c.flags()}
+				}
+				if opt.ValueType == NoType {
+					inputOpts[opt.Label] = true
+					continue
+				}
+				value := string(letters[j+1:])
+				if value == "" {
+					if i+1 >= len(input) {
+						return nil, nil, &InvalidCommandUsageError{command: c}
+					}
+					i++
+					value = input[i]
+				}
+				if err := c.setOption(inputOpts, opt, value); err != nil {
+					return nil, nil, err
+				}
+				break
+			}
+		default:
+			positionals = append(positionals, token)
+		}
+	}
+	return inputOpts, positionals, nil
+}
+
+// parseInput completes the options taken out of the input with the
+// environment, the configuration and their default value, then assigns the
+// positional tokens to the arguments, asking prompt for the required ones
+// missing when it is not nil.
+func (c *command) parseInput(inputOpts map[string]any, positionals []string, prompt argumentPrompter) (CommandInput, errors.Error) {
+	if err := c.setFromEnv(inputOpts); err != nil {
+		return nil, err
+	}
+	if err := c.setFromConfig(inputOpts); err != nil {
+		return nil, err
+	}
+	if err := c.validateOptions(inputOpts); err != nil {
+		return nil, err
+	}
+	c.setDefaults(inputOpts)
+
+	inputArgs, err := c.assignArguments(positionals, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commandInput{
+		arguments: inputArgs,
+		options:   inputOpts,
+	}, nil
+}
+
+// assignArguments maps positional tokens onto the arguments in position order.
+// Required arguments take one token each, optional ones take one when enough
+// tokens are left over, and the variadic argument takes whatever remains.
+// Missing required arguments are asked to prompt, if any.
+func (c *command) assignArguments(positionals []string, prompt argumentPrompter) (map[string]any, errors.Error) {
+	arguments, err := c.argumentsWithEnv()
+	if err != nil {
+		return nil, err
+	}
+	required := 0
+	for _, arg := range arguments {
+		if !arg.Optional {
+			required++
+		}
+	}
+	if len(positionals) < required && prompt != nil {
+		answers, err := c.promptArguments(arguments, len(positionals), prompt)
+		if err != nil {
+			return nil, err
+		}
+		positionals = append(positionals, answers...)
+	}
+	if len(positionals) < required {
+		return nil, &InvalidCommandUsageError{command: c}
+	}
+	extra := len(positionals) - required
+
+	inputArgs := make(map[string]any)
+	for _, arg := range arguments {
+		count := 0
+		if !arg.Optional {
+			count = 1
+		}
+		if arg.Variadic {
+			count += extra
+			extra = 0
+		} else if arg.Optional && extra > 0 {
+			count = 1
+			extra--
+		}
+		values := positionals[:count]
+		positionals = positionals[count:]
+		for _, value := range values {
+			if err := validateValue(arg.ValueType, arg.Choices, value); err != nil {
+				return nil, newInvalidValueError(c, "argument", arg.Label, arg.ValueType, value, err)
+			}
+		}
+		switch {
+		case arg.Variadic && count == 0 && arg.Default != "":
+			inputArgs[arg.Label] = []string{arg.Default}
+		case arg.Variadic:
+			inputArgs[arg.Label] = values
+		case count == 1:
+			inputArgs[arg.Label] = values[0]
+		case arg.Default != "":
+			inputArgs[arg.Label] = arg.Default
+		}
+	}
+	if extra > 0 {
+		return nil, &InvalidCommandUsageError{command: c}
+	}
+	return inputArgs, nil
+}
+
+// setDefaults sets the options that were not given to their default value.
+func (c *command) setDefaults(inputOpts map[string]any) {
+	for _, opt := range c.Options {
+		if _, given := inputOpts[opt.Label]; !given && opt.Default != "" {
+			inputOpts[opt.Label] = opt.Default
+		}
+	}
+}
+
+// leadingOptions returns the number of tokens at the start of input that are
+// options of the command or their values, such as the global options given
+// before the name of the command to run.
+func (c *command) leadingOptions(input []string) int {
+	i := 0
+	for i < len(input) {
+		token := input[i]
+		var opt CommandOption
+		var exists, inline bool
+		switch {
+		case token == OptionsTerminator:
+			return i
+		case strings.HasPrefix(token, OptionNamePrefix):
+			name, _, hasValue := strings.Cut(strings.TrimPrefix(token, OptionNamePrefix), "=")
+			opt, exists = c.findOptionByName(name)
+			inline = hasValue
+		case strings.HasPrefix(token, OptionLetterPrefix) && token != OptionLetterPrefix && !c.isNegativeNumber(token):
+			letters := []rune(strings.TrimPrefix(token, OptionLetterPrefix))
+			opt, exists = c.findOptionByLetter(letters[0])
+			inline = len(letters) > 1
+		}
+		if !exists {
+			return i
+		}
+		i++
+		if opt.ValueType != NoType && !inline {
+			i++
+		}
+	}
+	return min(i, len(input))
+}
+
+// shadowedBy returns the global options without those whose label cmd
+// declares, nor the flags it declares itself, as those of the command take
+// precedence.
+func (c *command) shadowedBy(cmd Command) *command {
+	declared, ok := cmd.(*command)
+	if !ok {
+		return c
+	}
+	globals := *c
+	globals.Options = nil
+	for _, opt := range c.Options {
+		if _, exists := declared.findOption(opt.Label); exists {
+			continue
+		}
+		if _, exists := declared.findOptionByName(opt.Name); exists {
+			opt.Name = ""
+		}
+		if _, exists := declared.findOptionByLetter(opt.Letter); exists {
+			opt.Letter = 0
+		}
+		globals.Options = append(globals.Options, opt)
+	}
+	return &globals
+}
+
+// resolveAfterOptions takes the global options given before the command name
+// out of in, and resolves the command to run from the tokens left.
+func (c *command) resolveAfterOptions(in []string, resolve func([]string) (Command, []string, errors.Error)) (map[string]any, Command, []string, errors.Error) {
+	head := c.leadingOptions(in)
+	globals, _, err := c.scanOptions(in[:head])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cmd, input, err := resolve(in[head:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return globals, cmd, input, nil
+}
+
+func (c *command) setOption(inputOpts map[string]any, opt CommandOption, value string) errors.Error {
+	if err := validateValue(opt.ValueType, opt.Choices, value); err != nil {
+		return newInvalidValueError(c, "option", opt.flag(), opt.ValueType, value, err)
+	}
+	inputOpts[opt.Label] = value
+	return nil
+}
+
+func (c *command) findOptionByName(name string) (CommandOption, bool) {
+	for _, opt := range c.Options {
+		if opt.Name != "" && opt.Name == name {
+			return opt, true
+		}
+	}
+	return CommandOption{}, false
+}
+
+func (c *command) findOptionByLetter(letter rune) (CommandOption, bool) {
+	for _, opt := range c.Options {
+		if opt.Letter != 0 && opt.Letter == letter {
+			return opt, true
+		}
+	}
+	return CommandOption{}, false
+}
+
+// isNegativeNumber reports whether token is a negative number, rather than a
+// short option, which is the case unless the command uses digits as letters.
+func (c *command) isNegativeNumber(token string) bool {
+	runes := []rune(token)
+	if len(runes) < 2 || !unicode.IsDigit(runes[1]) {
+		return false
+	}
+	_, exists := c.findOptionByLetter(runes[1])
+	return !exists
+}
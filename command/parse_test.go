@@ -0,0 +1,394 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func createParseCommand() Command {
+	comm := NewCommand("parse", "Parse test command.", func(CommandInput, operator.Operator) errors.Error { return nil })
+	comm.AddArgument(CommandArgument{Label: "count", Description: "A count", Position: 0, ValueType: TypeInt})
+	comm.AddOption(CommandOption{Label: "all", Description: "All", Letter: 'a', Name: "all"})
+	comm.AddOption(CommandOption{Label: "brief", Description: "Brief", Letter: 'b', Name: "brief"})
+	comm.AddOption(CommandOption{Label: "name", Description: "Name", Letter: 'n', Name: "name", ValueType: TypeString})
+	return comm
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	comm := createParseCommand()
+
+	parse := func(t *testing.T, in ...string) CommandInput {
+		input, err := comm.Parse(in)
+		assert.NoError(t, err)
+		return input
+	}
+	nameOpt := CommandOption{Label: "name", ValueType: TypeString}
+	countArg := CommandArgument{Label: "count", ValueType: TypeInt}
+
+	t.Run("Long Option With Equal Sign", func(t *testing.T) {
+		input := parse(t, "--name=alice", "3")
+		value, _ := input.ParseOption(nameOpt)
+		assert.Equal(t, "alice", value)
+		count, _ := input.ParseArgument(countArg)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("Long Option Value Containing Equal Sign", func(t *testing.T) {
+		value, _ := parse(t, "3", "--name=a=b").ParseOption(nameOpt)
+		assert.Equal(t, "a=b", value)
+	})
+
+	t.Run("Short Option With Attached Value", func(t *testing.T) {
+		value, _ := parse(t, "3", "-nalice").ParseOption(nameOpt)
+		assert.Equal(t, "alice", value)
+	})
+
+	t.Run("Bundled Short Flags", func(t *testing.T) {
+		input := parse(t, "-ab", "3")
+		all, _ := input.ParseOption(CommandOption{Label: "all"})
+		brief, _ := input.ParseOption(CommandOption{Label: "brief"})
+		assert.Equal(t, true, all)
+		assert.Equal(t, true, brief)
+	})
+
+	t.Run("Bundled Flags Ending With Valued Option", func(t *testing.T) {
+		input := parse(t, "-abn", "alice", "3")
+		value, _ := input.ParseOption(nameOpt)
+		assert.Equal(t, "alice", value)
+	})
+
+	t.Run("Options Terminator", func(t *testing.T) {
+		_, err := comm.Parse([]string{"--", "-a"})
+		assert.IsType(t, &InvalidValueError{}, err, "'-a' is an argument after '--' and not an int")
+
+		input := parse(t, "-a", "--", "-3")
+		count, _ := input.ParseArgument(countArg)
+		assert.Equal(t, -3, count)
+	})
+
+	t.Run("Negative Number Argument", func(t *testing.T) {
+		count, _ := parse(t, "-5").ParseArgument(countArg)
+		assert.Equal(t, -5, count)
+	})
+
+	t.Run("Missing Option Value", func(t *testing.T) {
+		_, err := comm.Parse([]string{"3", "--name"})
+		assert.IsType(t, &InvalidCommandUsageError{}, err)
+	})
+
+	t.Run("Value Given To Flag", func(t *testing.T) {
+		_, err := comm.Parse([]string{"3", "--all=yes"})
+		assert.IsType(t, &InvalidCommandUsageError{}, err)
+	})
+
+	t.Run("Unrecognized Bundled Flag", func(t *testing.T) {
+		_, err := comm.Parse([]string{"3", "-az"})
+		assert.IsType(t, &UnreconizedFlagError{}, err)
+	})
+
+	t.Run("Too Many Arguments", func(t *testing.T) {
+		_, err := comm.Parse([]string{"3", "4"})
+		assert.IsType(t, &InvalidCommandUsageError{}, err)
+	})
+}
+
+func TestParseArguments(t *testing.T) {
+	t.Parallel()
+	handler := func(CommandInput, operator.Operator) errors.Error { return nil }
+
+	copyCmd := NewCommand("copy", "Copy the files.", handler)
+	copyCmd.AddArgument(CommandArgument{Label: "src", Description: "Sources", Position: 0, ValueType: TypeString, Variadic: true})
+	copyCmd.AddArgument(CommandArgument{Label: "dst", Description: "Destination", Position: 1, ValueType: TypeString})
+
+	sumCmd := NewCommand("sum", "Sum the numbers.", handler)
+	sumCmd.AddArgument(CommandArgument{Label: "base", Description: "Base", Position: 0, ValueType: TypeInt, Optional: true, Default: "10"})
+	sumCmd.AddArgument(CommandArgument{Label: "numbers", Description: "Numbers", Position: 1, ValueType: TypeInt, Optional: true, Variadic: true})
+
+	t.Run("Variadic Before Fixed Argument", func(t *testing.T) {
+		input, err := copyCmd.Parse([]string{"a", "b", "c", "dir"})
+		assert.NoError(t, err)
+		src, _ := input.ParseArgument(CommandArgument{Label: "src", ValueType: TypeString})
+		dst, _ := input.ParseArgument(CommandArgument{Label: "dst", ValueType: TypeString})
+		assert.Equal(t, []string{"a", "b", "c"}, src)
+		assert.Equal(t, "dir", dst)
+	})
+
+	t.Run("Required Variadic Needs A Value", func(t *testing.T) {
+		_, err := copyCmd.Parse([]string{"dir"})
+		assert.IsType(t, &InvalidCommandUsageError{}, err)
+	})
+
+	t.Run("Default And Empty Variadic", func(t *testing.T) {
+		input, err := sumCmd.Parse([]string{})
+		assert.NoError(t, err)
+		base, _ := input.ParseArgument(CommandArgument{Label: "base", ValueType: TypeInt})
+		numbers, _ := input.ParseArgument(CommandArgument{Label: "numbers", ValueType: TypeInt})
+		assert.Equal(t, 10, base)
+		assert.Equal(t, []int{}, numbers)
+	})
+
+	t.Run("Typed Variadic Values", func(t *testing.T) {
+		input, err := sumCmd.Parse([]string{"1", "2", "3"})
+		assert.NoError(t, err)
+		base, _ := input.ParseArgument(CommandArgument{Label: "base", ValueType: TypeInt})
+		numbers, _ := input.ParseArgument(CommandArgument{Label: "numbers", ValueType: TypeInt})
+		assert.Equal(t, 1, base)
+		assert.Equal(t, []int{2, 3}, numbers)
+	})
+
+	t.Run("Invalid Variadic Value", func(t *testing.T) {
+		_, err := sumCmd.Parse([]string{"1", "2", "x"})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+
+	t.Run("Help Usage", func(t *testing.T) {
+		assert.Contains(t, copyCmd.Help(), "Usage: > copy src... dst")
+		assert.Contains(t, sumCmd.Help(), "Usage: > sum [base] [numbers...]")
+	})
+
+	t.Run("Invalid Setup", func(t *testing.T) {
+		_, err := copyCmd.AddArgument(CommandArgument{Label: "more", Position: 2, ValueType: TypeString, Variadic: true})
+		assert.IsType(t, &errors.SetupError{}, err, "only one variadic argument is allowed")
+		_, err = sumCmd.AddArgument(CommandArgument{Label: "mode", Position: 2, ValueType: TypeString, Default: "x"})
+		assert.IsType(t, &errors.SetupError{}, err, "default needs an optional argument")
+		_, err = sumCmd.AddArgument(CommandArgument{Label: "step", Position: 2, ValueType: TypeInt, Optional: true, Default: "x"})
+		assert.IsType(t, &errors.SetupError{}, err, "default needs to be valid")
+	})
+}
+
+func TestParseInvalidValues(t *testing.T) {
+	t.Parallel()
+	comm := NewCommand("deploy", "Deploy the application.", func(CommandInput, operator.Operator) errors.Error { return nil })
+	comm.AddArgument(CommandArgument{Label: "replicas", Description: "Replicas", Position: 0, ValueType: TypeInt})
+	comm.AddOption(CommandOption{Label: "env", Description: "Environment", Letter: 'e', Name: "env", ValueType: TypeEnum, Choices: []string{"dev", "prod"}})
+	comm.AddOption(CommandOption{Label: "timeout", Description: "Timeout", Letter: 't', ValueType: TypeDuration})
+
+	t.Run("Argument Named In Error", func(t *testing.T) {
+		_, err := comm.Parse([]string{"many"})
+		assert.Equal(t, `Invalid value "many" for argument replicas of command deploy: expected int (invalid syntax)`, err.Display())
+	})
+
+	t.Run("Enum Choices Enforced", func(t *testing.T) {
+		_, err := comm.Parse([]string{"2", "--env", "qa"})
+		assert.Equal(t, `Invalid value "qa" for option --env of command deploy: must be one of dev, prod`, err.Display())
+
+		input, err := comm.Parse([]string{"2", "--env=prod"})
+		assert.NoError(t, err)
+		env, _ := input.ParseOption(CommandOption{Label: "env", ValueType: TypeEnum})
+		assert.Equal(t, "prod", env)
+	})
+
+	t.Run("Short Option Named In Error", func(t *testing.T) {
+		_, err := comm.Parse([]string{"2", "-t", "soon"})
+		assert.Contains(t, err.Display(), "for option -t of command deploy: expected a duration")
+	})
+
+	t.Run("Enum Without Choices", func(t *testing.T) {
+		_, err := comm.AddOption(CommandOption{Label: "level", Name: "level", ValueType: TypeEnum})
+		assert.IsType(t, &errors.SetupError{}, err)
+	})
+
+	t.Run("Help Lists Choices", func(t *testing.T) {
+		assert.Contains(t, comm.Help(), "Environment (one of: dev, prod).")
+	})
+}
+
+func TestGlobalOptions(t *testing.T) {
+	t.Parallel()
+	writer := &mockOperator{}
+	commander := NewCommander().SetOperator(writer)
+	verboseOpt := CommandOption{Label: "verbose", Letter: 'v', Name: "verbose", Description: "Show more details"}
+	outputOpt := CommandOption{Label: "output", Letter: 'o', Name: "output", ValueType: TypeEnum, Choices: []string{"text", "json"}, Default: "text"}
+	_, err := commander.AddGlobalOption(verboseOpt)
+	assert.NoError(t, err)
+	_, err = commander.AddGlobalOption(outputOpt)
+	assert.NoError(t, err)
+	_, err = commander.AddGlobalOption(CommandOption{Label: "verbose", Name: "debug"})
+	assert.Error(t, err, "Global options should not share a label")
+
+	greet := NewCommand("greet", "Greet someone.", func(input CommandInput, operator operator.Operator) errors.Error {
+		name, _ := input.ParseArgument(CommandArgument{Label: "name", ValueType: TypeString})
+		verbose, _ := input.ParseOption(verboseOpt)
+		output, _ := input.ParseOption(outputOpt)
+		operator.Write(fmt.Sprintf("%v %v %v", name, verbose != nil, output))
+		return nil
+	})
+	greet.AddArgument(CommandArgument{Label: "name", Position: 0, ValueType: TypeString})
+	greet.AddOption(CommandOption{Label: "shout", Letter: 's', Name: "shout"})
+	commander.AddCommand("greet", greet)
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{"Defaults", []string{"greet", "alice"}, "alice false text"},
+		{"Before The Command", []string{"--verbose", "-o", "json", "greet", "alice"}, "alice true json"},
+		{"After The Command", []string{"greet", "alice", "--output=json", "-v"}, "alice true json"},
+		{"After The Terminator", []string{"greet", "--", "-v"}, "-v false text"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writer.Reset()
+			assert.NoError(t, commander.Run(test.input))
+			assert.Equal(t, test.expected, writer.String())
+		})
+	}
+
+	t.Run("Shadowed By The Command", func(t *testing.T) {
+		fileOpt := CommandOption{Label: "output", Letter: 'o', Name: "output", ValueType: TypeString}
+		build := NewCommand("build", "Build the project.", func(input CommandInput, operator operator.Operator) errors.Error {
+			file, _ := input.ParseOption(fileOpt)
+			verbose, _ := input.ParseOption(verboseOpt)
+			operator.Write(fmt.Sprintf("%v %v", file, verbose != nil))
+			return nil
+		})
+		build.AddOption(fileOpt)
+		commander.AddCommand("build", build)
+
+		for expected, input := range map[string][]string{
+			"foo.txt false": {"build", "--output", "foo.txt"},
+			"foo.txt true":  {"build", "-o", "foo.txt", "-v"},
+			"<nil> false":   {"build"},
+			"<nil> true":    {"--output", "json", "-v", "build"},
+		} {
+			writer.Reset()
+			assert.NoError(t, commander.Run(input), "input %v", input)
+			assert.Equal(t, expected, writer.String(), "input %v", input)
+		}
+	})
+
+	t.Run("Mixed Bundles", func(t *testing.T) {
+		writer.Reset()
+		assert.NoError(t, commander.Run([]string{"greet", "-sv", "alice"}))
+		assert.Equal(t, "alice true text", writer.String())
+	})
+
+	t.Run("Values Taken Before Global Flags", func(t *testing.T) {
+		messageOpt := CommandOption{Label: "message", Letter: 'm', Name: "message", ValueType: TypeString}
+		countOpt := CommandOption{Label: "count", Letter: 'n', Name: "count", ValueType: TypeInt}
+		say := NewCommand("say", "Say something.", func(input CommandInput, operator operator.Operator) errors.Error {
+			message, _ := input.ParseOption(messageOpt)
+			count, _ := input.ParseOption(countOpt)
+			verbose, _ := input.ParseOption(verboseOpt)
+			operator.Write(fmt.Sprintf("%v %v %v", message, count, verbose != nil))
+			return nil
+		})
+		say.AddOption(messageOpt)
+		say.AddOption(countOpt)
+		commander.AddCommand("say", say)
+
+		for expected, input := range map[string][]string{
+			"-v <nil> false":        {"say", "-m", "-v"},
+			"--verbose <nil> false": {"say", "--message", "--verbose"},
+			"<nil> 3 true":          {"say", "-vn", "3"},
+			"-v 3 true":             {"say", "-vn3", "-m-v"},
+		} {
+			writer.Reset()
+			assert.NoError(t, commander.Run(input), "input %v", input)
+			assert.Equal(t, expected, writer.String(), "input %v", input)
+		}
+	})
+
+	t.Run("Invalid Value", func(t *testing.T) {
+		err := commander.Run([]string{"greet", "alice", "--output", "xml"})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+
+	t.Run("Suggested For Unknown Flags", func(t *testing.T) {
+		err := commander.Run([]string{"greet", "alice", "--verbos"})
+		assert.Equal(t, "Unreconized flag --verbos for command greet\nDid you mean --verbose?", err.Display())
+	})
+
+	t.Run("Listed In Help", func(t *testing.T) {
+		writer.Reset()
+		commander.AddCommand("help", HelpCommand())
+		commander.AddGlobalOption(CommandOption{Label: "config", Name: "config-file", ValueType: TypeString, Description: "Configuration file"})
+		assert.NoError(t, commander.Run([]string{"help"}))
+		assert.Contains(t, writer.String(), "Global options:\n\t   -v | --verbose:  Show more details.\n")
+		assert.Contains(t, writer.String(), "\t   --config-file <string>:  Configuration file.\n", "Only the flags of the option should be listed")
+	})
+}
+
+func TestRequiredOptions(t *testing.T) {
+	t.Parallel()
+	comm := NewCommand("login", "Log in a user.", func(CommandInput, operator.Operator) errors.Error { return nil })
+	_, err := comm.AddOption(CommandOption{Label: "user", Letter: 'u', Name: "user", ValueType: TypeString, Required: true, Description: "Name of the user"})
+	assert.NoError(t, err)
+	_, err = comm.AddOption(CommandOption{Label: "realm", Name: "realm", ValueType: TypeString, Required: true, Default: "main"})
+	assert.IsType(t, &errors.SetupError{}, err, "Required options cannot have a default value")
+
+	_, err = comm.Parse([]string{"-u", "alice"})
+	assert.NoError(t, err)
+	_, err = comm.Parse([]string{})
+	assert.IsType(t, &MissingOptionError{}, err)
+	assert.Equal(t, "Missing required option --user for command login", err.Display())
+	assert.Contains(t, comm.Help(), "Name of the user (required).")
+}
+
+func TestOptionGroups(t *testing.T) {
+	t.Parallel()
+	comm := NewCommand("export", "Export the data.", func(CommandInput, operator.Operator) errors.Error { return nil })
+	for _, opt := range []CommandOption{
+		{Label: "json", Name: "json"},
+		{Label: "csv", Name: "csv"},
+		{Label: "quiet", Letter: 'q', Name: "quiet"},
+		{Label: "verbose", Letter: 'v', Name: "verbose"},
+		{Label: "user", Name: "user", ValueType: TypeString},
+		{Label: "password", Name: "password", ValueType: TypeString},
+		{Label: "limit", Name: "limit", ValueType: TypeInt, Default: "10"},
+	} {
+		_, err := comm.AddOption(opt)
+		assert.NoError(t, err)
+	}
+	_, err := comm.AddOptionGroup(GroupExactlyOne, "json", "csv")
+	assert.NoError(t, err)
+	_, err = comm.AddOptionGroup(GroupAtMostOne, "quiet", "verbose")
+	assert.NoError(t, err)
+	_, err = comm.AddOptionGroup(GroupTogether, "user", "password")
+	assert.NoError(t, err)
+
+	t.Run("Invalid Groups", func(t *testing.T) {
+		_, err := comm.AddOptionGroup(GroupAtMostOne, "json")
+		assert.IsType(t, &errors.SetupError{}, err)
+		_, err = comm.AddOptionGroup(GroupAtMostOne, "json", "xml")
+		assert.IsType(t, &errors.SetupError{}, err)
+		_, err = comm.AddOptionGroup(GroupTogether, "user", "limit")
+		assert.IsType(t, &errors.SetupError{}, err, "Defaulted options are always set")
+	})
+
+	tests := []struct {
+		name  string
+		input []string
+		err   string
+	}{
+		{"Valid", []string{"--json", "-q", "--user", "alice", "--password", "secret"}, ""},
+		{"Exactly One Missing", []string{"-q"}, "Invalid options for command export: one of --json, --csv is required"},
+		{"Exactly One Exceeded", []string{"--json", "--csv"}, "Invalid options for command export: --json and --csv cannot be used together"},
+		{"At Most One Exceeded", []string{"--csv", "-qv"}, "Invalid options for command export: --quiet and --verbose cannot be used together"},
+		{"Together Incomplete", []string{"--csv", "--password", "secret"}, "Invalid options for command export: --password must be used with --user"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := comm.Parse(test.input)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.IsType(t, &OptionGroupError{}, err)
+			assert.Equal(t, test.err, err.Display())
+		})
+	}
+
+	t.Run("Help", func(t *testing.T) {
+		help := comm.Help()
+		assert.Contains(t, help, "\t   Exactly one of: --json, --csv.\n")
+		assert.Contains(t, help, "\t   At most one of: --quiet, --verbose.\n")
+		assert.Contains(t, help, "\t   All or none of: --user, --password.\n")
+	})
+}
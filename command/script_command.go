@@ -0,0 +1,137 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// ScriptCommentPrefix starts the comment lines of scripts, which are skipped.
+const ScriptCommentPrefix = "#"
+
+// ParseLine splits a string by spaces, respecting quoted sections.
+func ParseLine(line string) []string {
+	var args []string
+	var currentArg strings.Builder
+	inQuote := false
+	for _, r := range line {
+		switch r {
+		case '"':
+			inQuote = !inQuote
+		case ' ':
+			if inQuote {
+				currentArg.WriteRune(r)
+			} else if currentArg.Len() > 0 {
+				args = append(args, currentArg.String())
+				currentArg.Reset()
+			}
+		default:
+			currentArg.WriteRune(r)
+		}
+	}
+	if currentArg.Len() > 0 {
+		args = append(args, currentArg.String())
+	}
+	return args
+}
+
+// RunScript runs the commands read from script, one per line, split with the
+// same rules as the interactive shell, each output followed by a new line.
+// Blank and comment lines are skipped, and the script ends early when a command
+// asks the application to exit.
+// It stops on the first failing command unless continueOnError is set, in
+// which case failures are written as they happen and reported once the whole
+// script ran. Errors name the script and the line they happened on.
+func RunScript(ctx context.Context, commander Commander, name string, script io.Reader, continueOnError bool) errors.Error {
+	scanner := bufio.NewScanner(script)
+	lineNumber, failures := 0, 0
+	for scanner.Scan() {
+		lineNumber++
+		if ctx.Err() != nil {
+			return &InterruptedError{command: name}
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ScriptCommentPrefix) {
+			continue
+		}
+		err := commander.RunContext(ctx, ParseLine(line))
+		if err != nil {
+			scriptErr := &ScriptError{script: name, line: lineNumber, err: err}
+			if !continueOnError {
+				return scriptErr
+			}
+			failures++
+			if err := commander.Write(scriptErr.Display()); err != nil {
+				return err
+			}
+		}
+		// Separate the output of each command like the interactive shell does
+		if err := commander.Write("\n"); err != nil {
+			return err
+		}
+		if _, exiting := commander.ExitStatus(); exiting {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.NewUnexpectedError(err)
+	}
+	if failures > 0 {
+		return &CommandError{message: fmt.Sprintf("%d command(s) of %s failed", failures, name)}
+	}
+	return nil
+}
+
+var scriptArg = CommandArgument{
+	Label:       "script",
+	Description: "Path of the script to run",
+	Position:    0,
+	ValueType:   TypeFile,
+}
+
+var continueOnErrorOpt = CommandOption{
+	Label:       "continue-on-error",
+	Letter:      'k',
+	Name:        "continue-on-error",
+	Description: "Keep running the script after a command fails",
+}
+
+func scriptHandler(ctx context.Context, input CommandInput, _ operator.Operator) errors.Error {
+	path, err := input.ParseArgument(scriptArg)
+	if err != nil {
+		return err
+	}
+	continueOnError, err := input.ParseOption(continueOnErrorOpt)
+	if err != nil {
+		return err
+	}
+	file, err_ := os.Open(path.(string))
+	if err_ != nil {
+		return errors.NewUnexpectedError(err_)
+	}
+	defer file.Close()
+	return RunScript(ctx, input.Commander(), path.(string), file, continueOnError != nil)
+}
+
+func scriptCommand(name string, description string) Command {
+	cmd := NewContextCommand(name, description, scriptHandler)
+	cmd.AddArgument(scriptArg)
+	cmd.AddOption(continueOnErrorOpt)
+	return cmd
+}
+
+func RunCommand() Command {
+	return scriptCommand("run", "Run the commands of a script file, one per line.")
+}
+
+// SourceCommand runs a script in the current interactive shell, so that the
+// working context it sets stays in effect afterwards.
+func SourceCommand() Command {
+	return scriptCommand("source", "Run the commands of a script file in the current shell.")
+}
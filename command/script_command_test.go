@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func TestParseLine(t *testing.T) {
+	assert.Equal(t, []string{"greet", "hello world", "-n", "2"}, ParseLine(`greet "hello world"  -n 2`))
+	assert.Empty(t, ParseLine("   "))
+}
+
+func createScriptCommander(writer *mockOperator) Commander {
+	commander := NewCommander().SetOperator(writer)
+	commander.AddCommand("echo", NewCommand("echo", "Echo the words.", func(input CommandInput, operator operator.Operator) errors.Error {
+		words, _ := input.ParseArgument(CommandArgument{Label: "words", ValueType: TypeString})
+		operator.Write(strings.Join(words.([]string), " ") + "\n")
+		return nil
+	}))
+	echo, _ := commander.Get("echo")
+	echo.AddArgument(CommandArgument{Label: "words", Position: 0, ValueType: TypeString, Variadic: true, Optional: true})
+	commander.AddCommand("run", RunCommand())
+	return commander
+}
+
+const testScript = `# greetings
+echo "hello world"
+
+unknown
+echo bye
+`
+
+func TestRunScript(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Stop On First Error", func(t *testing.T) {
+		writer := &mockOperator{}
+		err := RunScript(context.Background(), createScriptCommander(writer), "test.cli", strings.NewReader(testScript), false)
+		assert.IsType(t, &ScriptError{}, err)
+		assert.Equal(t, "test.cli:4: Invalid command: unknown", err.Display())
+		assert.Equal(t, "hello world\n\n", writer.String())
+	})
+
+	t.Run("Continue On Error", func(t *testing.T) {
+		writer := &mockOperator{}
+		err := RunScript(context.Background(), createScriptCommander(writer), "test.cli", strings.NewReader(testScript), true)
+		assert.Equal(t, "1 command(s) of test.cli failed", err.Display())
+		assert.Equal(t, "hello world\n\ntest.cli:4: Invalid command: unknown\nbye\n\n", writer.String())
+	})
+
+	t.Run("Interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RunScript(ctx, createScriptCommander(&mockOperator{}), "test.cli", strings.NewReader(testScript), false)
+		assert.IsType(t, &InterruptedError{}, err)
+	})
+
+	t.Run("Run Command", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.cli")
+		assert.NoError(t, os.WriteFile(path, []byte(testScript), 0o600))
+		writer := &mockOperator{}
+		commander := createScriptCommander(writer)
+
+		err := commander.Run([]string{"run", path})
+		assert.IsType(t, &ScriptError{}, err)
+		assert.Contains(t, err.Display(), "test.cli:4: Invalid command: unknown")
+
+		writer.Reset()
+		err = commander.Run([]string{"run", "--continue-on-error", path})
+		assert.IsType(t, &CommandError{}, err)
+		assert.Contains(t, writer.String(), "bye\n")
+	})
+}
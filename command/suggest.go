@@ -0,0 +1,124 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// DefaultSuggestionDistance is the largest number of edits, i.e. inserted,
+// removed, replaced or swapped characters, between an unknown command or flag
+// and the registered ones suggested in its place.
+const DefaultSuggestionDistance = 2
+
+// suggest returns the candidates within distance edits of name, closest first.
+// Leading dashes are ignored so that a long flag can suggest a short one, and a
+// candidate is only kept when less than half of it was edited, which rules out
+// suggesting any single letter flag for another. name itself is never
+// suggested.
+func suggest(name string, candidates []string, distance int) []string {
+	if distance <= 0 {
+		return nil
+	}
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	typed := strings.ToLower(strings.TrimLeft(name, OptionLetterPrefix))
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		target := strings.ToLower(strings.TrimLeft(candidate, OptionLetterPrefix))
+		d := editDistance(typed, target)
+		if d <= distance && 2*d < len([]rune(target)) && !slices.ContainsFunc(matches, func(m match) bool { return m.candidate == candidate }) {
+			matches = append(matches, match{candidate: candidate, distance: d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.candidate, b.candidate)
+	})
+	suggestions := make([]string, 0, len(matches))
+	for _, m := range matches {
+		suggestions = append(suggestions, m.candidate)
+	}
+	return suggestions
+}
+
+// editDistance returns the optimal string alignment distance between a and b:
+// the Levenshtein distance where swapping two adjacent characters counts as a
+// single edit.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
+
+// didYouMean formats suggestions to follow an error message.
+func didYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("\nDid you mean %s?", suggestions[0])
+	default:
+		return fmt.Sprintf("\nDid you mean one of %s?", strings.Join(suggestions, ", "))
+	}
+}
+
+// flags returns every flag of the command, as typed on the command line.
+func (c *command) flags() []string {
+	var flags []string
+	for _, opt := range c.Options {
+		if opt.Name != "" {
+			flags = append(flags, OptionNamePrefix+opt.Name)
+		}
+		if opt.Letter != 0 {
+			flags = append(flags, OptionLetterPrefix+string(opt.Letter))
+		}
+	}
+	return flags
+}
+
+// unknownSubCommand returns an InvalidCommandError suggesting the closest
+// visible subcommands of cmd when token names none of them. It returns nil
+// when token can be an option or an argument of cmd instead.
+func unknownSubCommand(cmd Command, token string, distance int) errors.Error {
+	c, ok := cmd.(*command)
+	if !ok || len(c.SubCommands) == 0 || len(c.Arguments) > 0 || strings.HasPrefix(token, OptionLetterPrefix) {
+		return nil
+	}
+	var names []string
+	for _, name := range c.GetSubCommands() {
+		if sub := c.SubCommands[name]; !sub.IsHidden() {
+			names = append(names, name)
+			names = append(names, sub.GetAliases()...)
+		}
+	}
+	return &InvalidCommandError{command: c.Path() + " " + token, suggestions: suggest(token, names, distance)}
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"help", "help", 0},
+		{"hlep", "help", 1},
+		{"versoin", "version", 1},
+		{"hstory", "history", 1},
+		{"exti", "exit", 1},
+		{"kitten", "sitting", 3},
+		{"", "run", 3},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.distance, editDistance(test.a, test.b), "Distance between %q and %q", test.a, test.b)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+	candidates := []string{"status", "start", "stop", "history"}
+	assert.Equal(t, []string{"status", "start"}, suggest("statu", candidates, 2))
+	assert.Equal(t, []string{"status"}, suggest("statsu", candidates, 1))
+	assert.Empty(t, suggest("deploy", candidates, 2))
+	assert.Empty(t, suggest("statu", candidates, 0), "A distance of 0 disables suggestions")
+
+	flags := []string{"--verbose", "-v", "--name", "-n"}
+	assert.Equal(t, []string{"--verbose"}, suggest("--verbos", flags, 2))
+	assert.Equal(t, []string{"-v"}, suggest("--v", flags, 2))
+	assert.Empty(t, suggest("-x", flags, 2), "Single letters should not suggest each other")
+	assert.Empty(t, suggest("-v", flags, 2), "The flag typed should not be suggested")
+}
+
+func TestSuggestionsInErrors(t *testing.T) {
+	t.Parallel()
+	commander := NewCommander().SetOperator(&mockOperator{})
+	commander.AddCommand("user", createUserCommand())
+	commander.AddCommand("help", HelpCommand())
+	commander.AddCommand(CompleteCommandName, CompleteCommand())
+
+	t.Run("Unknown Command", func(t *testing.T) {
+		err := commander.Run([]string{"usr"})
+		assert.IsType(t, &InvalidCommandError{}, err)
+		assert.Equal(t, "Invalid command: usr\nDid you mean user?", err.Display())
+	})
+
+	t.Run("Unknown Subcommand", func(t *testing.T) {
+		err := commander.Run([]string{"user", "lst"})
+		assert.IsType(t, &InvalidCommandError{}, err)
+		assert.Equal(t, "Invalid command: user lst\nDid you mean list?", err.Display())
+	})
+
+	t.Run("Hidden Commands Are Not Suggested", func(t *testing.T) {
+		err := commander.Run([]string{"__complet"})
+		assert.Equal(t, "Invalid command: __complet", err.Display())
+	})
+
+	t.Run("Unknown Flag", func(t *testing.T) {
+		err := commander.Run([]string{"help", "--comand=user"})
+		assert.IsType(t, &UnreconizedFlagError{}, err)
+		assert.Equal(t, "Unreconized flag --comand for command help\nDid you mean --command?", err.Display())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		commander := NewCommander().SetOperator(&mockOperator{}).SetSuggestionDistance(0)
+		commander.AddCommand("user", createUserCommand())
+		err := commander.Run([]string{"usr"})
+		assert.Equal(t, "Invalid command: usr", err.Display())
+	})
+}
@@ -0,0 +1,223 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// TypedCommandHandler handles a command whose arguments and options were bound
+// to the fields of a struct of type T.
+type TypedCommandHandler[T any] func(context.Context, T, operator.Operator) errors.Error
+
+// NewTypedCommand declares a command from the fields of the struct T tagged
+// with `cli`, and binds the parsed input to a fresh T before calling handler.
+//
+// Arguments are tagged `cli:"arg,pos=0"` and accept the optional, variadic and
+// default=<value> settings. Options are tagged `cli:"opt,short=v,long=verbose"`
+// and accept the required and default=<value> settings. Both accept
+// choices=<a|b|c> for enums, type=<name> to pick a value type by name instead
+// of from the field type and env=<NAME> to fall back to an environment
+// variable. Descriptions are read from the `desc` tag. Bool options are flags.
+//
+//	type deployInput struct {
+//		Target   string `cli:"arg,pos=0" desc:"Target environment"`
+//		Replicas int    `cli:"opt,short=r,long=replicas,default=3" desc:"Number of replicas"`
+//		DryRun   bool   `cli:"opt,long=dry-run" desc:"Only print the changes"`
+//	}
+func NewTypedCommand[T any](name string, description string, handler TypedCommandHandler[T]) (Command, errors.Error) {
+	structType := reflect.TypeFor[T]()
+	if structType.Kind() != reflect.Struct {
+		return nil, errors.NewSetupError(fmt.Sprintf("Command %s needs a struct to declare its input, got %s!", name, structType))
+	}
+	bindings, err := typedBindings(name, structType)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := NewContextCommand(name, description, func(ctx context.Context, input CommandInput, operator operator.Operator) errors.Error {
+		var target T
+		value := reflect.ValueOf(&target).Elem()
+		for _, binding := range bindings {
+			if err := binding.bind(input, value.Field(binding.field)); err != nil {
+				return err
+			}
+		}
+		return handler(ctx, target, operator)
+	})
+	for _, binding := range bindings {
+		var err errors.Error
+		if binding.argument != nil {
+			_, err = cmd.AddArgument(*binding.argument)
+		} else {
+			_, err = cmd.AddOption(*binding.option)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+type typedBinding struct {
+	field    int
+	argument *CommandArgument
+	option   *CommandOption
+}
+
+func (b typedBinding) bind(input CommandInput, field reflect.Value) errors.Error {
+	var value any
+	var err errors.Error
+	if b.argument != nil {
+		value, err = input.ParseArgument(*b.argument)
+	} else {
+		value, err = input.ParseOption(*b.option)
+	}
+	if err != nil || value == nil {
+		return err
+	}
+	parsed := reflect.ValueOf(value)
+	if !parsed.Type().AssignableTo(field.Type()) {
+		if !parsed.CanConvert(field.Type()) {
+			return &CommandError{message: fmt.Sprintf("Cannot bind a %s to a field of type %s", parsed.Type(), field.Type())}
+		}
+		parsed = parsed.Convert(field.Type())
+	}
+	field.Set(parsed)
+	return nil
+}
+
+func typedBindings(name string, structType reflect.Type) ([]typedBinding, errors.Error) {
+	var bindings []typedBinding
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		tag, tagged := field.Tag.Lookup("cli")
+		if !tagged || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, errors.NewSetupError(fmt.Sprintf("Field %s of command %s needs to be exported to be bound!", field.Name, name))
+		}
+		binding, err := typedBindingOf(name, i, field, tag)
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+func typedBindingOf(name string, index int, field reflect.StructField, tag string) (typedBinding, errors.Error) {
+	parts := strings.Split(tag, ",")
+	settings := make(map[string]string)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		settings[key] = value
+	}
+	var choices []string
+	if settings["choices"] != "" {
+		choices = strings.Split(settings["choices"], "|")
+	}
+	label := strings.ToLower(field.Name)
+	description := field.Tag.Get("desc")
+	setupError := func(format string, args ...any) errors.Error {
+		return errors.NewSetupError(fmt.Sprintf("Field %s of command %s: ", field.Name, name) + fmt.Sprintf(format, args...))
+	}
+
+	switch strings.TrimSpace(parts[0]) {
+	case "arg":
+		_, variadic := settings["variadic"]
+		_, optional := settings["optional"]
+		position, err := strconv.Atoi(settings["pos"])
+		if err != nil {
+			return typedBinding{}, setupError("arguments need a numeric pos setting")
+		}
+		valueGoType := field.Type
+		if variadic {
+			if field.Type.Kind() != reflect.Slice {
+				return typedBinding{}, setupError("variadic arguments need a slice field")
+			}
+			valueGoType = field.Type.Elem()
+		}
+		valueType, err_ := typedValueType(settings, choices, valueGoType, false)
+		if err_ != nil {
+			return typedBinding{}, setupError("%s", err_)
+		}
+		return typedBinding{field: index, argument: &CommandArgument{
+			Label:       label,
+			Description: description,
+			Position:    position,
+			ValueType:   valueType,
+			Choices:     choices,
+			Optional:    optional || settings["default"] != "",
+			Default:     settings["default"],
+			Variadic:    variadic,
+			Env:         settings["env"],
+		}}, nil
+	case "opt":
+		var letter rune
+		if short := settings["short"]; short != "" {
+			if utf8.RuneCountInString(short) != 1 {
+				return typedBinding{}, setupError("short needs to be a single letter, got %s", short)
+			}
+			letter, _ = utf8.DecodeRuneInString(short)
+		}
+		long := settings["long"]
+		if letter == 0 && long == "" {
+			return typedBinding{}, setupError("options need a short or long setting")
+		}
+		if long != "" {
+			label = long
+		}
+		_, required := settings["required"]
+		valueType, err := typedValueType(settings, choices, field.Type, true)
+		if err != nil {
+			return typedBinding{}, setupError("%s", err)
+		}
+		return typedBinding{field: index, option: &CommandOption{
+			Label:       label,
+			Description: description,
+			Letter:      letter,
+			Name:        long,
+			ValueType:   valueType,
+			Choices:     choices,
+			Default:     settings["default"],
+			Required:    required,
+			Env:         settings["env"],
+		}}, nil
+	default:
+		return typedBinding{}, setupError("expected arg or opt, got %s", parts[0])
+	}
+}
+
+// typedValueType picks the value type of a field, from its type setting or
+// else from its Go type. Bool options are flags and parse to NoType.
+func typedValueType(settings map[string]string, choices []string, goType reflect.Type, option bool) (ValueType, error) {
+	if typeName := settings["type"]; typeName != "" {
+		valueType, exists := valueTypeNamed(typeName)
+		if !exists {
+			return NoType, fmt.Errorf("unknown value type %s", typeName)
+		}
+		return valueType, nil
+	}
+	if len(choices) > 0 {
+		if goType.Kind() != reflect.String {
+			return NoType, fmt.Errorf("choices need a string field")
+		}
+		return TypeEnum, nil
+	}
+	if option && goType.Kind() == reflect.Bool {
+		return NoType, nil
+	}
+	valueType, exists := valueTypeOf(goType)
+	if !exists {
+		return NoType, fmt.Errorf("no value type registered for %s", goType)
+	}
+	return valueType, nil
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+type environment string
+
+type deployInput struct {
+	Target   environment       `cli:"arg,pos=0,choices=dev|prod" desc:"Target environment"`
+	Services []string          `cli:"arg,pos=1,variadic,optional" desc:"Services to deploy"`
+	Replicas int               `cli:"opt,short=r,long=replicas,default=3" desc:"Number of replicas"`
+	Timeout  time.Duration     `cli:"opt,long=timeout" desc:"Deployment timeout"`
+	DryRun   bool              `cli:"opt,short=n,long=dry-run" desc:"Only print the changes"`
+	Labels   map[string]string `cli:"opt,short=l" desc:"Labels to add"`
+	Note     string
+}
+
+func TestTypedCommand(t *testing.T) {
+	t.Parallel()
+	var got deployInput
+	cmd, err := NewTypedCommand("deploy", "Deploy the services.", func(_ context.Context, input deployInput, operator operator.Operator) errors.Error {
+		got = input
+		return nil
+	})
+	assert.NoError(t, err)
+
+	commander := NewCommander()
+	commander.SetOperator(&mockOperator{})
+	commander.AddCommand("deploy", cmd)
+
+	t.Run("Bind Input", func(t *testing.T) {
+		err := commander.Run([]string{"deploy", "prod", "api", "web", "-n", "--timeout=1m", "-l", "team=core"})
+		assert.NoError(t, err)
+		assert.Equal(t, deployInput{
+			Target:   "prod",
+			Services: []string{"api", "web"},
+			Replicas: 3,
+			Timeout:  time.Minute,
+			DryRun:   true,
+			Labels:   map[string]string{"team": "core"},
+		}, got)
+	})
+
+	t.Run("Fresh Input Per Run", func(t *testing.T) {
+		err := commander.Run([]string{"deploy", "dev", "-r", "5"})
+		assert.NoError(t, err)
+		assert.Equal(t, deployInput{Target: "dev", Services: []string{}, Replicas: 5}, got)
+	})
+
+	t.Run("Validate Choices", func(t *testing.T) {
+		err := commander.Run([]string{"deploy", "qa"})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+
+	t.Run("Generated Help", func(t *testing.T) {
+		help := cmd.Help()
+		assert.Contains(t, help, "Usage: > deploy target [services...] [options]")
+		assert.Contains(t, help, "-r | --replicas <int>:  Number of replicas (default: 3).")
+	})
+}
+
+func TestTypedCommandSetup(t *testing.T) {
+	t.Parallel()
+	t.Run("Not A Struct", func(t *testing.T) {
+		_, err := NewTypedCommand("bad", "Bad input type.", func(context.Context, int, operator.Operator) errors.Error { return nil })
+		assert.IsType(t, &errors.SetupError{}, err)
+	})
+
+	t.Run("Missing Position", func(t *testing.T) {
+		type input struct {
+			Name string `cli:"arg"`
+		}
+		_, err := NewTypedCommand("bad", "Bad input type.", func(context.Context, input, operator.Operator) errors.Error { return nil })
+		assert.IsType(t, &errors.SetupError{}, err)
+	})
+
+	t.Run("Unsupported Field Type", func(t *testing.T) {
+		type input struct {
+			Done chan bool `cli:"opt,long=done"`
+		}
+		_, err := NewTypedCommand("bad", "Bad input type.", func(context.Context, input, operator.Operator) errors.Error { return nil })
+		assert.IsType(t, &errors.SetupError{}, err)
+		assert.Contains(t, err.Error(), "no value type registered for chan bool")
+	})
+
+	t.Run("Type By Name", func(t *testing.T) {
+		type input struct {
+			Manifest string `cli:"opt,long=manifest,type=file"`
+		}
+		cmd, err := NewTypedCommand("apply", "Apply a manifest.", func(context.Context, input, operator.Operator) errors.Error { return nil })
+		assert.NoError(t, err)
+		_, err = cmd.Parse([]string{"--manifest", fmt.Sprintf("%s/missing", t.TempDir())})
+		assert.IsType(t, &InvalidValueError{}, err)
+	})
+}
+
+func TestTypedCommandRequiredOption(t *testing.T) {
+	t.Parallel()
+	type loginInput struct {
+		User string `cli:"opt,short=u,long=user,required" desc:"Name of the user"`
+	}
+	cmd, err := NewTypedCommand("login", "Log in a user.", func(context.Context, loginInput, operator.Operator) errors.Error { return nil })
+	assert.NoError(t, err)
+	_, err = cmd.Parse([]string{})
+	assert.IsType(t, &MissingOptionError{}, err)
+}
@@ -2,7 +2,16 @@ package command
 
 import (
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -11,23 +20,281 @@ const (
 	TypeFloat
 	TypeBool
 	TypeString
+	TypeDuration
+	// TypeEnum values are strings restricted to the Choices of their argument
+	// or option.
+	TypeEnum
+	// TypeStringSlice and TypeIntSlice values are comma-separated lists.
+	TypeStringSlice
+	TypeIntSlice
+	// TypeStringMap values are comma-separated key=value pairs.
+	TypeStringMap
+	// TypeTime values are RFC3339 timestamps.
+	TypeTime
+	TypeURL
+	TypeIP
+	// TypeFile values are paths to existing regular files.
+	TypeFile
 )
 
+// ValueParser converts the raw text of an argument or option into its value.
+// Custom parsers are made available to commands with RegisterValueType.
+type ValueParser interface {
+	// TypeName is the name of the type shown in help and error messages.
+	TypeName() string
+	Parse(string) (any, error)
+}
+
+// ValueHinter can be implemented by a ValueParser to give an example of a
+// valid input, shown in help.
+type ValueHinter interface {
+	Hint() string
+}
+
+type valueParser[T any] struct {
+	name  string
+	hint  string
+	parse func(string) (T, error)
+}
+
+// NewValueParser builds a ValueParser from a typed parse function. Variadic
+// arguments of its type parse to a []T.
+func NewValueParser[T any](name string, hint string, parse func(string) (T, error)) ValueParser {
+	return &valueParser[T]{name: name, hint: hint, parse: parse}
+}
+
+func (p *valueParser[T]) TypeName() string {
+	return p.name
+}
+
+func (p *valueParser[T]) Hint() string {
+	return p.hint
+}
+
+func (p *valueParser[T]) Parse(s string) (any, error) {
+	value, err := p.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (p *valueParser[T]) goType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+var (
+	valueParsersMu sync.RWMutex
+	valueParsers   = map[ValueType]ValueParser{
+		TypeInt:         NewValueParser("int", "", strconv.Atoi),
+		TypeFloat:       NewValueParser("float", "", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }),
+		TypeBool:        NewValueParser("bool", "", strconv.ParseBool),
+		TypeString:      NewValueParser("string", "", func(s string) (string, error) { return s, nil }),
+		TypeDuration:    NewValueParser("duration", "1h30m", parseDuration),
+		TypeEnum:        NewValueParser("enum", "", func(s string) (string, error) { return s, nil }),
+		TypeStringSlice: NewValueParser("list", "a,b,c", func(s string) ([]string, error) { return splitList(s), nil }),
+		TypeIntSlice:    NewValueParser("int list", "1,2,3", parseIntSlice),
+		TypeStringMap:   NewValueParser("key=value list", "key=value,other=value", parseStringMap),
+		TypeTime:        NewValueParser("time", time.RFC3339, parseTime),
+		TypeURL:         NewValueParser("url", "https://example.com", parseURL),
+		TypeIP:          NewValueParser("ip", "10.0.0.1", parseIP),
+		TypeFile:        NewValueParser("file", "", parseFile),
+	}
+	nextValueType = TypeFile + 1
+)
+
+// RegisterValueType makes parser available to commands under a newly
+// allocated ValueType, which arguments and options can then use.
+func RegisterValueType(parser ValueParser) ValueType {
+	valueParsersMu.Lock()
+	defer valueParsersMu.Unlock()
+	valueType := nextValueType
+	nextValueType++
+	valueParsers[valueType] = parser
+	return valueType
+}
+
+// LookupValueParser returns the parser registered for valueType.
+func LookupValueParser(valueType ValueType) (ValueParser, bool) {
+	valueParsersMu.RLock()
+	defer valueParsersMu.RUnlock()
+	parser, exists := valueParsers[valueType]
+	return parser, exists
+}
+
 func ParseValue(valueType ValueType, value any) (interface{}, error) {
 	valueString, ok := value.(string)
 	if !ok {
 		return nil, fmt.Errorf("cant cast to string")
 	}
-	switch valueType {
-	case TypeInt:
-		return strconv.Atoi(valueString) // Parse string to int
-	case TypeFloat:
-		return strconv.ParseFloat(valueString, 64) // Parse string to float64
-	case TypeBool:
-		return strconv.ParseBool(valueString) // Parse string to bool
-	case TypeString:
-		return valueString, nil // No parsing needed for strings
-	default:
+	parser, exists := LookupValueParser(valueType)
+	if !exists {
+		return nil, fmt.Errorf("unsupported type")
+	}
+	return parser.Parse(valueString)
+}
+
+// ParseValues parses every value with ParseValue and returns them as a slice
+// typed after valueType, e.g. []int for TypeInt. Parsers that do not expose
+// their Go type, i.e. not built with NewValueParser, yield a []any.
+func ParseValues(valueType ValueType, values []string) (any, error) {
+	parser, exists := LookupValueParser(valueType)
+	if !exists {
 		return nil, fmt.Errorf("unsupported type")
 	}
+	elemType := reflect.TypeFor[any]()
+	if typed, ok := parser.(interface{ goType() reflect.Type }); ok {
+		elemType = typed.goType()
+	}
+	result := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(values))
+	for _, value := range values {
+		parsed, err := parser.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		item := reflect.ValueOf(parsed)
+		if !item.IsValid() {
+			item = reflect.Zero(elemType)
+		}
+		result = reflect.Append(result, item)
+	}
+	return result.Interface(), nil
+}
+
+// TypeName returns the human readable name of a value type, as shown in help
+// and error messages.
+func TypeName(valueType ValueType) string {
+	if parser, exists := LookupValueParser(valueType); exists {
+		return parser.TypeName()
+	}
+	return ""
+}
+
+// valueTypeOf returns the first registered value type, in registration order,
+// whose parser produces values of the Go type t.
+func valueTypeOf(t reflect.Type) (ValueType, bool) {
+	valueParsersMu.RLock()
+	defer valueParsersMu.RUnlock()
+	for _, valueType := range slices.Sorted(maps.Keys(valueParsers)) {
+		typed, ok := valueParsers[valueType].(interface{ goType() reflect.Type })
+		if ok && typed.goType() == t {
+			return valueType, true
+		}
+	}
+	return NoType, false
+}
+
+// valueTypeNamed returns the registered value type called name.
+func valueTypeNamed(name string) (ValueType, bool) {
+	valueParsersMu.RLock()
+	defer valueParsersMu.RUnlock()
+	for valueType, parser := range valueParsers {
+		if parser.TypeName() == name {
+			return valueType, true
+		}
+	}
+	return NoType, false
+}
+
+// typeHint returns an example input for valueType, if its parser gives one.
+func typeHint(valueType ValueType) string {
+	parser, exists := LookupValueParser(valueType)
+	if !exists {
+		return ""
+	}
+	if hinter, ok := parser.(ValueHinter); ok {
+		return hinter.Hint()
+	}
+	return ""
+}
+
+// validateValue checks that value parses as valueType and, for enums, that it
+// is one of the choices.
+func validateValue(valueType ValueType, choices []string, value string) error {
+	if _, err := ParseValue(valueType, value); err != nil {
+		return err
+	}
+	if valueType == TypeEnum && !slices.Contains(choices, value) {
+		return fmt.Errorf("must be one of %s", strings.Join(choices, ", "))
+	}
+	return nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration such as 300ms or 1h30m")
+	}
+	return d, nil
+}
+
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return []string{}
+	}
+	items := strings.Split(s, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+func parseIntSlice(s string) ([]int, error) {
+	items := splitList(s)
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("expected a comma-separated list of integers, %q is not an integer", item)
+		}
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+func parseStringMap(s string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range splitList(s) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("expected comma-separated key=value pairs, %q is not a pair", item)
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC3339 timestamp such as 2006-01-02T15:04:05Z")
+	}
+	return t, nil
+}
+
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("expected an absolute URL such as https://example.com")
+	}
+	return u, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("expected an IPv4 or IPv6 address")
+	}
+	return ip, nil
+}
+
+func parseFile(s string) (string, error) {
+	info, err := os.Stat(s)
+	if err != nil {
+		return "", fmt.Errorf("file %s does not exist", s)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", s)
+	}
+	return s, nil
 }
@@ -1,7 +1,12 @@
 package command
 
 import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -65,3 +70,143 @@ func TestParseValue(t *testing.T) {
 		assert.Equal(t, "cant cast to string", err.Error())
 	})
 }
+
+func TestParseValues(t *testing.T) {
+	t.Run("Typed Slice", func(t *testing.T) {
+		values, err := ParseValues(TypeFloat, []string{"1.5", "2"})
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1.5, 2}, values)
+	})
+
+	t.Run("Invalid Value", func(t *testing.T) {
+		_, err := ParseValues(TypeBool, []string{"true", "nope"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRichValues(t *testing.T) {
+	t.Run("Duration", func(t *testing.T) {
+		value, err := ParseValue(TypeDuration, "1h30m")
+		assert.NoError(t, err)
+		assert.Equal(t, 90*time.Minute, value)
+		_, err = ParseValue(TypeDuration, "later")
+		assert.Error(t, err)
+	})
+
+	t.Run("Slices", func(t *testing.T) {
+		value, err := ParseValue(TypeStringSlice, "a, b,c")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, value)
+		value, err = ParseValue(TypeIntSlice, "1,2")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, value)
+		_, err = ParseValue(TypeIntSlice, "1,two")
+		assert.ErrorContains(t, err, `"two" is not an integer`)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		value, err := ParseValue(TypeStringMap, "env=prod,region=eu")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"env": "prod", "region": "eu"}, value)
+		_, err = ParseValue(TypeStringMap, "env")
+		assert.Error(t, err)
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		value, err := ParseValue(TypeTime, "2024-05-01T10:00:00Z")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), value)
+		_, err = ParseValue(TypeTime, "yesterday")
+		assert.Error(t, err)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		_, err := ParseValue(TypeURL, "https://example.com/path")
+		assert.NoError(t, err)
+		_, err = ParseValue(TypeURL, "example")
+		assert.Error(t, err)
+	})
+
+	t.Run("IP", func(t *testing.T) {
+		value, err := ParseValue(TypeIP, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, net.ParseIP("10.0.0.1").Equal(value.(net.IP)))
+		_, err = ParseValue(TypeIP, "10.0.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "file.txt")
+		assert.NoError(t, os.WriteFile(path, []byte("content"), 0o600))
+		value, err := ParseValue(TypeFile, path)
+		assert.NoError(t, err)
+		assert.Equal(t, path, value)
+		_, err = ParseValue(TypeFile, dir)
+		assert.ErrorContains(t, err, "not a regular file")
+		_, err = ParseValue(TypeFile, filepath.Join(dir, "missing"))
+		assert.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("Type Names", func(t *testing.T) {
+		assert.Equal(t, "duration", TypeName(TypeDuration))
+		assert.Equal(t, "", TypeName(NoType))
+	})
+}
+
+type semver struct {
+	major, minor, patch int
+}
+
+func parseSemver(s string) (semver, error) {
+	var v semver
+	if _, err := fmt.Sscanf(s, "%d.%d.%d", &v.major, &v.minor, &v.patch); err != nil {
+		return semver{}, fmt.Errorf("expected a semantic version such as 1.2.3")
+	}
+	return v, nil
+}
+
+func TestRegisterValueType(t *testing.T) {
+	typeSemver := RegisterValueType(NewValueParser("semver", "1.2.3", parseSemver))
+
+	t.Run("Parse Custom Value", func(t *testing.T) {
+		value, err := ParseValue(typeSemver, "1.4.2")
+		assert.NoError(t, err)
+		assert.Equal(t, semver{1, 4, 2}, value)
+		_, err = ParseValue(typeSemver, "one")
+		assert.EqualError(t, err, "expected a semantic version such as 1.2.3")
+	})
+
+	t.Run("Typed Slice Of Custom Values", func(t *testing.T) {
+		values, err := ParseValues(typeSemver, []string{"1.0.0", "2.0.0"})
+		assert.NoError(t, err)
+		assert.Equal(t, []semver{{1, 0, 0}, {2, 0, 0}}, values)
+	})
+
+	t.Run("Distinct Value Types", func(t *testing.T) {
+		other := RegisterValueType(NewValueParser("other", "", parseSemver))
+		assert.NotEqual(t, typeSemver, other)
+		assert.Equal(t, "semver", TypeName(typeSemver))
+		parser, exists := LookupValueParser(other)
+		assert.True(t, exists)
+		assert.Equal(t, "other", parser.TypeName())
+	})
+
+	t.Run("Used By Commands", func(t *testing.T) {
+		comm := createSampleCommand()
+		comm.AddOption(CommandOption{Label: "target", Description: "Target version", Letter: 't', Name: "target", ValueType: typeSemver})
+
+		input, err := comm.Parse([]string{"value", "--target", "3.1.0"})
+		assert.NoError(t, err)
+		target, err := input.ParseOption(CommandOption{Label: "target", ValueType: typeSemver})
+		assert.NoError(t, err)
+		assert.Equal(t, semver{3, 1, 0}, target)
+
+		_, err = comm.Parse([]string{"value", "--target", "latest"})
+		assert.Contains(t, err.Display(), "for option --target of command test: expected a semantic version")
+		assert.Contains(t, comm.Help(), "-t | --target <semver>:  Target version (e.g. 1.2.3).")
+
+		comm.AddArgument(CommandArgument{Label: "since", Description: "Version to start from", Position: 1, ValueType: typeSemver, Optional: true})
+		assert.Contains(t, comm.Help(), "\t   [since] <semver>:  Version to start from (e.g. 1.2.3).\n")
+	})
+}
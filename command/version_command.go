@@ -5,26 +5,26 @@ import (
 	"github.com/yassirdeveloper/cli/operator"
 )
 
-var version string
+func GetVersionString(commander Commander) string {
+	return "v" + commander.GetVersion()
+}
 
-func GetVersionString() string {
-	return "v" + version
+// versionOutput is the structured output of version.
+type versionOutput struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
 }
 
-func versionHandler(_ CommandInput, operator operator.Operator) errors.Error {
-	err := operator.Write(GetVersionString())
-	if err != nil {
-		return errors.NewUnexpectedError(err)
-	}
-	return nil
+func versionHandler(input CommandInput, operator operator.Operator) errors.Error {
+	commander := input.Commander()
+	version := versionOutput{Name: commander.GetName(), Version: commander.GetVersion()}
+	return WriteOutput(input, operator, TextOutput(GetVersionString(commander), version))
 }
 
-func VersionCommand(v string) Command {
-	cmd := NewCommand(
+func VersionCommand() Command {
+	return NewCommand(
 		"version",
 		"Display the current version.",
 		versionHandler,
 	)
-	version = v
-	return cmd
 }
@@ -7,17 +7,20 @@ import (
 )
 
 func TestVersionCommand(t *testing.T) {
+	t.Parallel()
 	// Set up a mock writer to capture output
 	writer := &mockOperator{}
 
 	// Create the VersionCommand
-	version = "3.6.8"
-	versionCommand := createVersionCommand(version)
+	version := "3.6.8"
+	versionCommand := createVersionCommand()
+	commander := NewCommander().SetVersion(version)
 
-	// Create a mock CommandInput (not used in this case, but required by the interface)
+	// Create a mock CommandInput carrying the commander the version is read from
 	input := &commandInput{
 		arguments: make(map[string]any),
 		options:   make(map[string]any),
+		commander: commander,
 	}
 
 	t.Run("Valid Version Output", func(t *testing.T) {
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/yassirdeveloper/cli/command"
+)
+
+// completer completes the interactive shell input from the command tree of a
+// commander, implementing readline.AutoCompleter.
+type completer struct {
+	commander command.Commander
+}
+
+// Do returns the suffixes completing the word under the cursor, along with the
+// length of the part of it already typed.
+func (c *completer) Do(line []rune, pos int) ([][]rune, int) {
+	input := string(line[:pos])
+	words := command.ParseLine(input)
+	if len(words) == 0 || strings.HasSuffix(input, " ") {
+		words = append(words, "")
+	}
+	current := words[len(words)-1]
+	completions, _ := command.Complete(c.commander, words)
+	suffixes := make([][]rune, 0, len(completions))
+	for _, completion := range completions {
+		suffix := strings.TrimPrefix(completion.Value, current)
+		if !strings.HasSuffix(completion.Value, "=") {
+			suffix += " "
+		}
+		suffixes = append(suffixes, []rune(suffix))
+	}
+	return suffixes, len([]rune(current))
+}
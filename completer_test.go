@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yassirdeveloper/cli/command"
+	"github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+func complete(c *completer, line string) ([]string, int) {
+	suffixes, length := c.Do([]rune(line), len([]rune(line)))
+	result := []string{}
+	for _, suffix := range suffixes {
+		result = append(result, string(suffix))
+	}
+	return result, length
+}
+
+func TestCompleter(t *testing.T) {
+	t.Parallel()
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+
+	deploy := command.NewCommand(
+		"deploy",
+		"Deploy the application",
+		func(command.CommandInput, operator.Operator) errors.Error { return nil },
+	)
+	deploy.AddArgument(command.CommandArgument{
+		Label:     "service",
+		Position:  0,
+		ValueType: command.TypeString,
+		Completer: func(prefix string) []command.Completion {
+			return []command.Completion{{Value: "api"}, {Value: "web"}}
+		},
+	})
+	deploy.AddOption(command.CommandOption{Label: "env", Letter: 'e', Name: "env", ValueType: command.TypeEnum, Choices: []string{"dev", "prod"}})
+	cli.AddCommand(deploy)
+	c := &completer{commander: cli.commander}
+
+	t.Run("Command Names", func(t *testing.T) {
+		suffixes, length := complete(c, "ver")
+		assert.Equal(t, []string{"sion "}, suffixes)
+		assert.Equal(t, 3, length)
+	})
+
+	t.Run("Empty Line", func(t *testing.T) {
+		suffixes, length := complete(c, "")
+		assert.Contains(t, suffixes, "deploy ")
+		assert.NotContains(t, suffixes, command.CompleteCommandName+" ", "Hidden commands should not be completed")
+		assert.Equal(t, 0, length)
+	})
+
+	t.Run("Option Flags", func(t *testing.T) {
+		suffixes, length := complete(c, "deploy --e")
+		assert.Equal(t, []string{"nv "}, suffixes)
+		assert.Equal(t, 3, length)
+	})
+
+	t.Run("Option Values", func(t *testing.T) {
+		suffixes, _ := complete(c, "deploy --env p")
+		assert.Equal(t, []string{"rod "}, suffixes)
+	})
+
+	t.Run("Dynamic Argument Values", func(t *testing.T) {
+		suffixes, length := complete(c, "deploy -e dev ")
+		assert.Equal(t, []string{"api ", "web "}, suffixes)
+		assert.Equal(t, 0, length)
+	})
+}
@@ -2,11 +2,47 @@ package errors
 
 import "fmt"
 
+// Exit codes the process ends with when a command fails in one-shot mode.
+const (
+	ExitSuccess = 0
+	// ExitFailure is returned when a command handler fails.
+	ExitFailure = 1
+	// ExitUsage is returned when a command is called with invalid arguments
+	// or options.
+	ExitUsage = 2
+	// ExitUnexpected is returned on internal errors (EX_SOFTWARE).
+	ExitUnexpected = 70
+	// ExitConfig is returned when a configuration file is invalid (EX_CONFIG).
+	ExitConfig = 78
+	// ExitInterrupted is returned when a command is interrupted by Ctrl+C.
+	ExitInterrupted = 130
+	// ExitUnknownCommand is returned when no command matches the input.
+	ExitUnknownCommand = 127
+)
+
 type Error interface {
 	Error() string
 	Display() string
 }
 
+// ExitCoder is implemented by the errors choosing the exit code of the
+// process, the others exit with ExitFailure.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the code the process exits with on err: ExitSuccess when it
+// is nil, its own code when it is an ExitCoder, ExitFailure otherwise.
+func ExitCode(err Error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	if coder, ok := err.(ExitCoder); ok {
+		return coder.ExitCode()
+	}
+	return ExitFailure
+}
+
 type BaseError struct {
 	message string
 }
@@ -20,12 +56,43 @@ func (e *BaseError) Display() string {
 	return e.message
 }
 
+func (e *BaseError) ExitCode() int {
+	return ExitFailure
+}
+
 func New(msg string) Error {
 	return &BaseError{
 		message: msg,
 	}
 }
 
+type exitCodeError struct {
+	err      Error
+	exitCode int
+}
+
+// WithExitCode returns err with its exit code replaced by exitCode.
+func WithExitCode(err Error, exitCode int) Error {
+	return &exitCodeError{err: err, exitCode: exitCode}
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Display() string {
+	return e.err.Display()
+}
+
+func (e *exitCodeError) ExitCode() int {
+	return e.exitCode
+}
+
+// Unwrap returns the error whose exit code was replaced.
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 type unexpectedError struct {
 	message string
 	err     error
@@ -48,6 +115,15 @@ func (e *unexpectedError) Display() string {
 	return "An unexpected error occured!"
 }
 
+func (e *unexpectedError) ExitCode() int {
+	return ExitUnexpected
+}
+
+// Unwrap returns the error that was not expected.
+func (e *unexpectedError) Unwrap() error {
+	return e.err
+}
+
 type SetupError struct {
 	message string
 }
@@ -63,3 +139,7 @@ func (e *SetupError) Error() string {
 func (e *SetupError) Display() string {
 	return "An error occured during setup : " + e.message
 }
+
+func (e *SetupError) ExitCode() int {
+	return ExitUnexpected
+}
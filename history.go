@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultHistoryFile returns the file the history of the interactive shell of
+// the CLI called name is saved to by default: $XDG_STATE_HOME/<name>/history,
+// falling back to ~/.local/state/<name>/history. It returns an empty string,
+// disabling persistence, when no home directory can be found.
+func DefaultHistoryFile(name string) string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, name, "history")
+}
+
+// history keeps the entries of the interactive shell, up to limit of them,
+// and mirrors them to a file when it has a path.
+type history struct {
+	path    string
+	limit   int
+	entries []string
+	// onClear is called after the history is cleared, to reset the copy kept
+	// by readline for navigation.
+	onClear func()
+}
+
+func newHistory(path string, limit int) *history {
+	return &history{path: path, limit: limit}
+}
+
+// load reads the entries saved in the history file, keeping the most recent
+// ones within the limit. A missing file is an empty history.
+func (h *history) load() error {
+	h.entries = nil
+	if h.path == "" {
+		return nil
+	}
+	file, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if h.limit > 0 && len(h.entries) > h.limit {
+		h.truncate()
+		return h.save()
+	}
+	return nil
+}
+
+// add records entry unless it is empty, starts with a space or repeats the
+// previous entry, and reports whether it was recorded.
+func (h *history) add(entry string) (bool, error) {
+	entry = strings.TrimSuffix(entry, "\n")
+	if strings.TrimSpace(entry) == "" || strings.HasPrefix(entry, " ") {
+		return false, nil
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return false, nil
+	}
+	h.entries = append(h.entries, entry)
+	h.truncate()
+	return true, h.save()
+}
+
+func (h *history) truncate() {
+	if h.limit > 0 && len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+}
+
+// save rewrites the history file with the current entries, so that it never
+// holds more than limit of them.
+func (h *history) save() error {
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	content := strings.Join(h.entries, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(h.path, []byte(content), 0o600)
+}
+
+func (h *history) Entries() []string {
+	return h.entries
+}
+
+func (h *history) Clear() error {
+	h.entries = nil
+	if h.onClear != nil {
+		h.onClear()
+	}
+	return h.save()
+}
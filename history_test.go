@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHistoryFile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/state")
+	assert.Equal(t, filepath.Join("/state", "my-cli", "history"), DefaultHistoryFile("my-cli"))
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	assert.Equal(t, filepath.Join("/home/user", ".local", "state", "my-cli", "history"), DefaultHistoryFile("my-cli"))
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "my-cli", "history")
+
+	t.Run("Persist Entries", func(t *testing.T) {
+		h := newHistory(path, 3)
+		assert.NoError(t, h.load(), "A missing history file should be an empty history")
+		for _, entry := range []string{"version", "help", "help", " secret", "", "greet"} {
+			_, err := h.add(entry)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []string{"version", "help", "greet"}, h.Entries(), "Duplicate, space-prefixed and empty entries should be skipped")
+
+		reloaded := newHistory(path, 3)
+		assert.NoError(t, reloaded.load())
+		assert.Equal(t, h.Entries(), reloaded.Entries())
+	})
+
+	t.Run("Honor Limit On Disk", func(t *testing.T) {
+		h := newHistory(path, 3)
+		assert.NoError(t, h.load())
+		_, err := h.add("exit")
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "help\ngreet\nexit\n", string(content))
+
+		shorter := newHistory(path, 2)
+		assert.NoError(t, shorter.load())
+		content, _ = os.ReadFile(path)
+		assert.Equal(t, "greet\nexit\n", string(content), "Loading with a lower limit should truncate the file")
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		h := newHistory(path, 3)
+		assert.NoError(t, h.load())
+		cleared := false
+		h.onClear = func() { cleared = true }
+		assert.NoError(t, h.Clear())
+		assert.True(t, cleared)
+		assert.Empty(t, h.Entries())
+		content, _ := os.ReadFile(path)
+		assert.Empty(t, string(content))
+	})
+
+	t.Run("In Memory", func(t *testing.T) {
+		h := newHistory("", 3)
+		recorded, err := h.add("version")
+		assert.NoError(t, err)
+		assert.True(t, recorded)
+	})
+}
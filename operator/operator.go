@@ -2,9 +2,12 @@ package operator
 
 import (
 	"bufio"
+	stderrors "errors"
 	"io"
 	"os"
+	"strings"
 
+	"github.com/chzyer/readline"
 	"github.com/yassirdeveloper/cli/errors"
 )
 
@@ -13,11 +16,20 @@ type Operator interface {
 	Read() (string, errors.Error)
 }
 
+// Terminal is implemented by operators that can tell whether they write to a
+// terminal, and how many columns wide it is.
+type Terminal interface {
+	IsTerminal() bool
+	Width() int
+}
+
 type stdOperator struct {
 	delim       byte
 	maxReadSize int
 	writer      io.Writer
 	reader      *bufio.Reader
+	// input is the file reader reads from, when it is one.
+	input *os.File
 }
 
 func (o *stdOperator) Write(s string) errors.Error {
@@ -36,11 +48,68 @@ func (o *stdOperator) Read() (string, errors.Error) {
 	return s, nil
 }
 
+// ReadLine reads an answer after writing the prompt. The answer is not echoed
+// when it is read from a pipe, so a line break is written after it instead.
+func (o *stdOperator) ReadLine(prompt string) (string, errors.Error) {
+	if err := o.Write(prompt); err != nil {
+		return "", err
+	}
+	line, err := o.Read()
+	if !o.interactive() {
+		o.Write("\n")
+	}
+	if err != nil && (line == "" || !stderrors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ReadPassword reads an answer with the echo of the terminal turned off, or
+// like any other answer when the input is not a terminal.
+func (o *stdOperator) ReadPassword(prompt string) (string, errors.Error) {
+	if !o.interactive() {
+		return o.ReadLine(prompt)
+	}
+	if err := o.Write(prompt); err != nil {
+		return "", err
+	}
+	password, err := readline.ReadPassword(int(o.input.Fd()))
+	o.Write("\n")
+	if err != nil {
+		return "", errors.NewUnexpectedError(err)
+	}
+	return string(password), nil
+}
+
+// interactive reports whether the answers are typed in a terminal.
+func (o *stdOperator) interactive() bool {
+	return o.input != nil && readline.IsTerminal(int(o.input.Fd()))
+}
+
+func (o *stdOperator) IsTerminal() bool {
+	file, ok := o.writer.(*os.File)
+	return ok && readline.IsTerminal(int(file.Fd()))
+}
+
+// Width returns the width of the terminal, or 0 when it is unknown.
+func (o *stdOperator) Width() int {
+	file, ok := o.writer.(*os.File)
+	if !ok {
+		return 0
+	}
+	width, _, err := readline.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
 func NewStdOperator(delim byte, maxReadSize int) *stdOperator {
 	return &stdOperator{
 		delim:       delim,
 		maxReadSize: maxReadSize,
 		writer:      os.Stdout,
 		reader:      bufio.NewReader(os.Stdin),
+		input:       os.Stdin,
 	}
 }
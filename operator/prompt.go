@@ -0,0 +1,212 @@
+package operator
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// MaxAttempts is the number of answers a prompt reads before giving up when
+// they are all invalid.
+const MaxAttempts = 3
+
+// Prompter is implemented by operators that show the prompt themselves while
+// reading the answer, like line editors do. Other operators get the prompt
+// written before their Read is called.
+type Prompter interface {
+	ReadLine(prompt string) (string, errors.Error)
+	// ReadPassword reads an answer without echoing it.
+	ReadPassword(prompt string) (string, errors.Error)
+}
+
+// PromptError is returned when a prompt gets no valid answer.
+type PromptError struct {
+	prompt  string
+	message string
+}
+
+func (e *PromptError) Error() string {
+	return fmt.Sprintf("%s: %s", e.prompt, e.message)
+}
+
+func (e *PromptError) Display() string {
+	return fmt.Sprintf("No valid answer to \"%s\": %s", e.prompt, e.message)
+}
+
+func (e *PromptError) ExitCode() int {
+	return errors.ExitUsage
+}
+
+// Input asks for a line of text until validate accepts it, at most MaxAttempts
+// times. The error returned by validate is shown before asking again, a nil
+// validate accepts any answer.
+func Input(operator Operator, prompt string, validate func(string) error) (string, errors.Error) {
+	return ask(operator, prompt, prompt+": ", func(answer string) (string, error) {
+		if validate == nil {
+			return answer, nil
+		}
+		return answer, validate(answer)
+	})
+}
+
+// Confirm asks a yes or no question, an empty answer picks def.
+func Confirm(operator Operator, question string, def bool) (bool, errors.Error) {
+	hint := " [y/N]: "
+	if def {
+		hint = " [Y/n]: "
+	}
+	return ask(operator, question, question+hint, func(answer string) (bool, error) {
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		return false, stderrors.New("please answer yes or no")
+	})
+}
+
+// Password asks for a secret without echoing it, when the operator supports
+// it. Operators reading from a pipe or a script get it like any other answer.
+func Password(operator Operator, prompt string) (string, errors.Error) {
+	read := func(prompt string) (string, errors.Error) {
+		if prompter, ok := operator.(Prompter); ok {
+			return prompter.ReadPassword(prompt)
+		}
+		return readLine(operator, prompt)
+	}
+	password, err := read(prompt + ": ")
+	if err != nil {
+		return "", noAnswer(prompt, err)
+	}
+	return password, nil
+}
+
+// Select asks to pick one of the choices, by number or by name. An empty
+// answer picks def, unless it is empty too.
+func Select(operator Operator, prompt string, choices []string, def string) (string, errors.Error) {
+	if err := listChoices(operator, prompt, choices); err != nil {
+		return "", err
+	}
+	hint := "Choice: "
+	if def != "" {
+		hint = fmt.Sprintf("Choice [%s]: ", def)
+	}
+	return ask(operator, prompt, hint, func(answer string) (string, error) {
+		if answer == "" {
+			answer = def
+		}
+		return pick(choices, answer)
+	})
+}
+
+// MultiSelect asks to pick any number of the choices, by number or by name,
+// separated by commas or spaces. An empty answer picks defs. The choices are
+// returned in the order they are listed in.
+func MultiSelect(operator Operator, prompt string, choices []string, defs []string) ([]string, errors.Error) {
+	if err := listChoices(operator, prompt, choices); err != nil {
+		return nil, err
+	}
+	hint := "Choices (comma separated): "
+	if len(defs) > 0 {
+		hint = fmt.Sprintf("Choices (comma separated) [%s]: ", strings.Join(defs, ", "))
+	}
+	return ask(operator, prompt, hint, func(answer string) ([]string, error) {
+		answers := strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' })
+		if len(answers) == 0 {
+			answers = defs
+		}
+		picked := make(map[string]bool)
+		for _, answer := range answers {
+			choice, err := pick(choices, answer)
+			if err != nil {
+				return nil, err
+			}
+			picked[choice] = true
+		}
+		var selected []string
+		for _, choice := range choices {
+			if picked[choice] {
+				selected = append(selected, choice)
+			}
+		}
+		return selected, nil
+	})
+}
+
+// ask reads answers until parse accepts one, writing the reason of each
+// rejection, at most MaxAttempts times.
+func ask[T any](operator Operator, prompt string, hint string, parse func(string) (T, error)) (T, errors.Error) {
+	var zero T
+	for attempt := 1; ; attempt++ {
+		answer, err := readLine(operator, hint)
+		if err != nil {
+			return zero, noAnswer(prompt, err)
+		}
+		value, err_ := parse(strings.TrimSpace(answer))
+		if err_ == nil {
+			return value, nil
+		}
+		if attempt == MaxAttempts {
+			return zero, &PromptError{prompt: prompt, message: err_.Error()}
+		}
+		if err := operator.Write(fmt.Sprintf("Invalid answer: %s\n", err_)); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// readLine reads an answer after showing the prompt, without its delimiter.
+// The last line of the input is taken even when it is not terminated.
+func readLine(operator Operator, prompt string) (string, errors.Error) {
+	if prompter, ok := operator.(Prompter); ok {
+		return prompter.ReadLine(prompt)
+	}
+	if err := operator.Write(prompt); err != nil {
+		return "", err
+	}
+	line, err := operator.Read()
+	if err != nil && (line == "" || !stderrors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// noAnswer turns the end of the input into a PromptError, as there is no one
+// left to answer.
+func noAnswer(prompt string, err errors.Error) errors.Error {
+	if stderrors.Is(err, io.EOF) {
+		return &PromptError{prompt: prompt, message: "the input ended"}
+	}
+	return err
+}
+
+func listChoices(operator Operator, prompt string, choices []string) errors.Error {
+	builder := &strings.Builder{}
+	builder.WriteString(prompt + ":\n")
+	for i, choice := range choices {
+		fmt.Fprintf(builder, "  %d) %s\n", i+1, choice)
+	}
+	return operator.Write(builder.String())
+}
+
+// pick returns the choice answer names, by its number or its name.
+func pick(choices []string, answer string) (string, error) {
+	if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(choices) {
+		return choices[number-1], nil
+	}
+	if i := slices.IndexFunc(choices, func(choice string) bool { return strings.EqualFold(choice, answer) }); i >= 0 {
+		return choices[i], nil
+	}
+	if answer == "" {
+		return "", stderrors.New("a choice is required")
+	}
+	return "", fmt.Errorf("%s is not one of the choices", answer)
+}
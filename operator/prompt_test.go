@@ -0,0 +1,153 @@
+package operator
+
+import (
+	"bufio"
+	"bytes"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		answer   string
+		def      bool
+		expected bool
+	}{
+		{"y", false, true},
+		{"YES", false, true},
+		{"n", true, false},
+		{"", true, true},
+		{"", false, false},
+	} {
+		confirmed, err := Confirm(NewScriptedOperator(test.answer), "Continue?", test.def)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, confirmed, "answer %q", test.answer)
+	}
+
+	t.Run("Retries", func(t *testing.T) {
+		operator := NewScriptedOperator("maybe", "yes")
+		confirmed, err := Confirm(operator, "Continue?", false)
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+		assert.Equal(t, "Continue? [y/N]: maybe\nInvalid answer: please answer yes or no\nContinue? [y/N]: yes\n", operator.Output())
+	})
+
+	t.Run("End Of Input", func(t *testing.T) {
+		_, err := Confirm(NewScriptedOperator(), "Continue?", true)
+		assert.IsType(t, &PromptError{}, err)
+		assert.Equal(t, "No valid answer to \"Continue?\": the input ended", err.Display())
+	})
+}
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+
+	notEmpty := func(answer string) error {
+		if answer == "" {
+			return stderrors.New("a name is required")
+		}
+		return nil
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		name, err := Input(NewScriptedOperator("", "  bob "), "Name", notEmpty)
+		assert.NoError(t, err)
+		assert.Equal(t, "bob", name)
+	})
+
+	t.Run("Too Many Attempts", func(t *testing.T) {
+		operator := NewScriptedOperator("", "", "", "bob")
+		_, err := Input(operator, "Name", notEmpty)
+		assert.IsType(t, &PromptError{}, err)
+		assert.Equal(t, "Name: a name is required", err.Error())
+		assert.Equal(t, MaxAttempts, strings.Count(operator.Output(), "Name: "))
+	})
+
+	t.Run("No Validation", func(t *testing.T) {
+		answer, err := Input(NewScriptedOperator(""), "Comment", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, answer)
+	})
+}
+
+func TestPassword(t *testing.T) {
+	password, err := Password(NewScriptedOperator("s3cret"), "Password")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	choices := []string{"dev", "staging", "prod"}
+
+	t.Run("By Number", func(t *testing.T) {
+		operator := NewScriptedOperator("2")
+		choice, err := Select(operator, "Environment", choices, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", choice)
+		assert.Equal(t, "Environment:\n  1) dev\n  2) staging\n  3) prod\nChoice: 2\n", operator.Output())
+	})
+
+	t.Run("By Name", func(t *testing.T) {
+		choice, err := Select(NewScriptedOperator("4", "Prod"), "Environment", choices, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", choice)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		choice, err := Select(NewScriptedOperator(""), "Environment", choices, "dev")
+		assert.NoError(t, err)
+		assert.Equal(t, "dev", choice)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		_, err := Select(NewScriptedOperator("", "", ""), "Environment", choices, "")
+		assert.Equal(t, "Environment: a choice is required", err.Error())
+	})
+}
+
+func TestMultiSelect(t *testing.T) {
+	t.Parallel()
+	choices := []string{"read", "write", "admin"}
+
+	selected, err := MultiSelect(NewScriptedOperator("admin, 1 read"), "Permissions", choices, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read", "admin"}, selected)
+
+	selected, err = MultiSelect(NewScriptedOperator(""), "Permissions", choices, []string{"write"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"write"}, selected)
+
+	selected, err = MultiSelect(NewScriptedOperator(""), "Permissions", choices, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, selected)
+
+	_, err = MultiSelect(NewScriptedOperator("read,owner", "", ""), "Permissions", choices, []string{"owner"})
+	assert.Equal(t, "Permissions: owner is not one of the choices", err.Error())
+}
+
+func TestStdOperatorPrompts(t *testing.T) {
+	var buf bytes.Buffer
+	op := &stdOperator{
+		delim:  '\n',
+		writer: &buf,
+		reader: bufio.NewReader(strings.NewReader("yes\ns3cret")),
+	}
+
+	confirmed, err := Confirm(op, "Continue?", false)
+	assert.NoError(t, err)
+	assert.True(t, confirmed)
+
+	password, err := Password(op, "Password")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+	assert.Equal(t, "Continue? [y/N]: \nPassword: \n", buf.String())
+
+	_, err = Password(op, "Password")
+	assert.IsType(t, &PromptError{}, err)
+}
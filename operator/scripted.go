@@ -0,0 +1,43 @@
+package operator
+
+import (
+	"io"
+	"strings"
+
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// ScriptedOperator is an Operator reading its input from a script of answers,
+// and recording everything written to it, to test prompts and handlers.
+type ScriptedOperator struct {
+	answers []string
+	output  strings.Builder
+}
+
+func NewScriptedOperator(answers ...string) *ScriptedOperator {
+	return &ScriptedOperator{answers: answers}
+}
+
+func (o *ScriptedOperator) Write(s string) errors.Error {
+	o.output.WriteString(s)
+	return nil
+}
+
+// Read returns the next answer followed by a line break, the answers are
+// echoed like they would be in a terminal. Once they are all read, it fails
+// with io.EOF.
+func (o *ScriptedOperator) Read() (string, errors.Error) {
+	if len(o.answers) == 0 {
+		return "", errors.NewUnexpectedError(io.EOF)
+	}
+	answer := o.answers[0]
+	o.answers = o.answers[1:]
+	o.output.WriteString(answer + "\n")
+	return answer + "\n", nil
+}
+
+// Output returns everything written to the operator, along with the answers
+// it read.
+func (o *ScriptedOperator) Output() string {
+	return o.output.String()
+}
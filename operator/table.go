@@ -0,0 +1,271 @@
+package operator
+
+import (
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/yassirdeveloper/cli/errors"
+)
+
+// Alignment is the way the cells of a column are aligned.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+const (
+	columnSeparator = "  "
+	ellipsis        = "…"
+	minColumnWidth  = 3
+)
+
+// Table lays out rows of cells in aligned columns. Widths are measured in
+// terminal cells, so wide runes count twice and combining marks not at all.
+type Table struct {
+	headers  []string
+	align    []Alignment
+	rows     [][]string
+	border   bool
+	maxWidth int
+}
+
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// SetAlignment sets the alignment of a column, columns are left aligned by
+// default.
+func (t *Table) SetAlignment(column int, align Alignment) *Table {
+	for len(t.align) <= column {
+		t.align = append(t.align, AlignLeft)
+	}
+	t.align[column] = align
+	return t
+}
+
+// SetBorder draws a border around the table and between its columns.
+func (t *Table) SetBorder(border bool) *Table {
+	t.border = border
+	return t
+}
+
+// SetMaxWidth limits the width of the table, the widest columns are truncated
+// until it fits. A width of 0 means no limit.
+func (t *Table) SetMaxWidth(width int) *Table {
+	t.maxWidth = width
+	return t
+}
+
+func (t *Table) AddRow(cells ...string) *Table {
+	t.rows = append(t.rows, cells)
+	return t
+}
+
+// Render writes the table to the operator. When the operator is not a terminal
+// the table is written as tab separated values, which is easier to process.
+func (t *Table) Render(operator Operator) errors.Error {
+	return operator.Write(t.RenderString(operator))
+}
+
+// RenderString returns the table as Render writes it to the operator: tab
+// separated values unless it is a terminal, else aligned columns fitting its
+// width when the table has no maximum width of its own.
+func (t *Table) RenderString(operator Operator) string {
+	terminal, ok := operator.(Terminal)
+	if !ok || !terminal.IsTerminal() {
+		return t.TSV()
+	}
+	if t.maxWidth > 0 {
+		return t.String()
+	}
+	table := *t
+	table.maxWidth = terminal.Width()
+	return table.String()
+}
+
+// TSV returns the table as tab separated values, one line per row. Tabs and
+// line breaks within cells are replaced with spaces.
+func (t *Table) TSV() string {
+	builder := &strings.Builder{}
+	for _, row := range t.lines() {
+		for i, cell := range row {
+			if i > 0 {
+				builder.WriteByte('\t')
+			}
+			builder.WriteString(flatten(cell))
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// String returns the table with its columns aligned.
+func (t *Table) String() string {
+	lines := t.lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	widths := t.widths(lines)
+	rule := t.rule(widths)
+
+	builder := &strings.Builder{}
+	builder.WriteString(rule)
+	for i, row := range lines {
+		t.writeRow(builder, row, widths)
+		if i == 0 && len(t.headers) > 0 {
+			builder.WriteString(rule)
+		}
+	}
+	if len(lines) > 1 || len(t.headers) == 0 {
+		builder.WriteString(rule)
+	}
+	return builder.String()
+}
+
+// lines returns the header and the rows, all padded to the same number of
+// cells.
+func (t *Table) lines() [][]string {
+	columns := len(t.headers)
+	for _, row := range t.rows {
+		columns = max(columns, len(row))
+	}
+	var lines [][]string
+	if len(t.headers) > 0 {
+		lines = append(lines, t.headers)
+	}
+	lines = append(lines, t.rows...)
+	for i, line := range lines {
+		if len(line) < columns {
+			padded := make([]string, columns)
+			copy(padded, line)
+			lines[i] = padded
+		}
+	}
+	return lines
+}
+
+// widths returns the width of each column, shrinking the widest ones when the
+// table is wider than its maximum width.
+func (t *Table) widths(lines [][]string) []int {
+	widths := make([]int, len(lines[0]))
+	for _, line := range lines {
+		for i, cell := range line {
+			widths[i] = max(widths[i], Width(flatten(cell)))
+		}
+	}
+	if t.maxWidth <= 0 {
+		return widths
+	}
+	for t.width(widths) > t.maxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// width returns the width of the table with the given column widths.
+func (t *Table) width(widths []int) int {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	if t.border {
+		return total + 3*len(widths) + 1
+	}
+	return total + len(columnSeparator)*(len(widths)-1)
+}
+
+func (t *Table) rule(widths []int) string {
+	if !t.border {
+		return ""
+	}
+	builder := &strings.Builder{}
+	for _, width := range widths {
+		builder.WriteString("+" + strings.Repeat("-", width+2))
+	}
+	builder.WriteString("+\n")
+	return builder.String()
+}
+
+func (t *Table) writeRow(builder *strings.Builder, row []string, widths []int) {
+	line := &strings.Builder{}
+	if t.border {
+		line.WriteString("| ")
+	}
+	for i, cell := range row {
+		if i > 0 {
+			if t.border {
+				line.WriteString(" | ")
+			} else {
+				line.WriteString(columnSeparator)
+			}
+		}
+		line.WriteString(t.pad(Truncate(flatten(cell), widths[i]), widths[i], i))
+	}
+	if t.border {
+		line.WriteString(" |")
+		builder.WriteString(line.String())
+	} else {
+		builder.WriteString(strings.TrimRight(line.String(), " "))
+	}
+	builder.WriteByte('\n')
+}
+
+func (t *Table) pad(cell string, width int, column int) string {
+	space := width - Width(cell)
+	if space <= 0 {
+		return cell
+	}
+	align := AlignLeft
+	if column < len(t.align) {
+		align = t.align[column]
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", space) + cell
+	case AlignCenter:
+		return strings.Repeat(" ", space/2) + cell + strings.Repeat(" ", space-space/2)
+	default:
+		return cell + strings.Repeat(" ", space)
+	}
+}
+
+// Width returns the number of terminal cells taken by s.
+func Width(s string) int {
+	return readline.Runes{}.WidthAll([]rune(s))
+}
+
+// Truncate shortens s to at most width terminal cells, marking the cut with
+// an ellipsis.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	builder := &strings.Builder{}
+	used := 0
+	for _, r := range s {
+		w := readline.Runes{}.Width(r)
+		if used+w > width-Width(ellipsis) {
+			break
+		}
+		builder.WriteRune(r)
+		used += w
+	}
+	builder.WriteString(ellipsis)
+	return builder.String()
+}
+
+func flatten(cell string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(cell)
+}
@@ -0,0 +1,92 @@
+package operator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type terminalOperator struct {
+	stdOperator
+	width int
+}
+
+func (o *terminalOperator) IsTerminal() bool {
+	return true
+}
+
+func (o *terminalOperator) Width() int {
+	return o.width
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Aligned Columns", func(t *testing.T) {
+		table := NewTable("NAME", "AGE").SetAlignment(1, AlignRight).
+			AddRow("alice", "7").
+			AddRow("bob", "42")
+		assert.Equal(t, "NAME   AGE\nalice    7\nbob     42\n", table.String())
+	})
+
+	t.Run("Center Alignment", func(t *testing.T) {
+		table := NewTable("A", "MIDDLE", "B").SetAlignment(1, AlignCenter).AddRow("x", "y", "z")
+		assert.Equal(t, "A  MIDDLE  B\nx    y     z\n", table.String())
+	})
+
+	t.Run("Borders", func(t *testing.T) {
+		table := NewTable("NAME", "AGE").SetBorder(true).AddRow("bob", "42")
+		expected := "+------+-----+\n" +
+			"| NAME | AGE |\n" +
+			"+------+-----+\n" +
+			"| bob  | 42  |\n" +
+			"+------+-----+\n"
+		assert.Equal(t, expected, table.String())
+	})
+
+	t.Run("Missing Cells", func(t *testing.T) {
+		table := NewTable("A", "B", "C").AddRow("1").AddRow("1", "2", "3")
+		assert.Equal(t, "A  B  C\n1\n1  2  3\n", table.String())
+	})
+
+	t.Run("Wide Runes", func(t *testing.T) {
+		table := NewTable("NAME", "CITY").AddRow("東京", "tokyo").AddRow("café", "paris")
+		assert.Equal(t, "NAME  CITY\n東京  tokyo\ncafé  paris\n", table.String())
+	})
+
+	t.Run("Truncation", func(t *testing.T) {
+		table := NewTable("KEY", "DESCRIPTION").SetMaxWidth(20).
+			AddRow("name", "the name of the user to greet")
+		assert.Equal(t, "KEY   DESCRIPTION\nname  the name of t…\n", table.String())
+		for _, line := range []string{"KEY   DESCRIPTION", "name  the name of t…"} {
+			assert.LessOrEqual(t, Width(line), 20)
+		}
+	})
+
+	t.Run("TSV", func(t *testing.T) {
+		table := NewTable("NAME", "NOTE").AddRow("bob", "two\nlines").AddRow("alice")
+		assert.Equal(t, "NAME\tNOTE\nbob\ttwo lines\nalice\t\n", table.TSV())
+	})
+}
+
+func TestTableRender(t *testing.T) {
+	table := NewTable("NAME", "DESCRIPTION").AddRow("bob", "a friendly person")
+
+	var buf bytes.Buffer
+	assert.NoError(t, table.Render(&stdOperator{writer: &buf}))
+	assert.Equal(t, "NAME\tDESCRIPTION\nbob\ta friendly person\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, table.Render(&terminalOperator{stdOperator: stdOperator{writer: &buf}, width: 16}))
+	assert.Equal(t, "NAME  DESCRIPTI…\nbob   a friendl…\n", buf.String())
+	assert.Equal(t, buf.String(), table.RenderString(&terminalOperator{width: 16}), "RenderString should return what Render writes")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", Truncate("hello", 5))
+	assert.Equal(t, "hel…", Truncate("hello", 4))
+	assert.Equal(t, "東…", Truncate("東京都", 4))
+	assert.Equal(t, 4, Width("東京"))
+	assert.Equal(t, 4, Width("café"))
+}
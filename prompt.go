@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DEFAULT_PROMPT renders the name of the CLI followed by its symbol.
+const DEFAULT_PROMPT = "{{.Name}}{{.Symbol}} "
+
+// PromptState is what the prompt of the interactive shell is rendered from,
+// re-evaluated before each line is read.
+type PromptState struct {
+	Name   string
+	Symbol string
+	// Context is the working context set by commands with
+	// Commander.SetWorkingContext, e.g. the selected project.
+	Context string
+	// Status is the exit code of the previous command, 0 when it succeeded.
+	Status int
+	// Elapsed is how long the previous command took to run.
+	Elapsed time.Duration
+}
+
+var promptColors = map[string]string{
+	"bold":    "\033[1m",
+	"dim":     "\033[2m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+}
+
+var promptFuncs = template.FuncMap{
+	// color wraps text with the ANSI escape codes of the named color or style.
+	"color": func(name string, text any) string {
+		code, exists := promptColors[name]
+		if !exists {
+			return fmt.Sprint(text)
+		}
+		return code + fmt.Sprint(text) + "\033[0m"
+	},
+	// duration rounds a duration to a readable precision.
+	"duration": func(d time.Duration) string {
+		if d < time.Second {
+			return d.Round(time.Millisecond).String()
+		}
+		return d.Round(10 * time.Millisecond).String()
+	},
+}
+
+func parsePrompt(prompt string) (*template.Template, error) {
+	return template.New("prompt").Funcs(promptFuncs).Parse(prompt)
+}
+
+// SetPrompt sets the text/template the prompt is rendered from with the
+// PromptState fields, e.g. `{{.Name}}{{if .Context}}({{.Context}}){{end}}{{.Symbol}} `.
+// The color function styles part of it: `{{color "red" .Symbol}}`, and the
+// duration function formats Elapsed.
+func (cli *Cli) SetPrompt(prompt string) (*Cli, error) {
+	tmpl, err := parsePrompt(prompt)
+	if err != nil {
+		return nil, err
+	}
+	cli.prompt = tmpl
+	return cli, nil
+}
+
+// SetPromptFunc sets a callback rendering the prompt, taking precedence over
+// the prompt template.
+func (cli *Cli) SetPromptFunc(promptFunc func(PromptState) string) *Cli {
+	cli.promptFunc = promptFunc
+	return cli
+}
+
+func (cli *Cli) renderPrompt(state PromptState) string {
+	if cli.promptFunc != nil {
+		return cli.promptFunc(state)
+	}
+	builder := &strings.Builder{}
+	if cli.prompt == nil || cli.prompt.Execute(builder, state) != nil {
+		return state.Name + state.Symbol + " "
+	}
+	return builder.String()
+}
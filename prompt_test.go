@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompt(t *testing.T) {
+	t.Parallel()
+	cli, err := NewCli("test-cli", "0.0.0")
+	assert.NoError(t, err, "No error should occur for valid cli")
+	state := PromptState{Name: "test-cli", Symbol: "$", Context: "prod", Status: 1, Elapsed: 1234567 * time.Microsecond}
+
+	t.Run("Default Prompt", func(t *testing.T) {
+		assert.Equal(t, "test-cli> ", cli.renderPrompt(PromptState{Name: cli.Name, Symbol: cli.Symbol}))
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		_, err := cli.SetPrompt(`{{.Name}}{{if .Context}}({{.Context}}){{end}} [{{duration .Elapsed}}] {{if .Status}}{{color "red" .Symbol}}{{else}}{{.Symbol}}{{end}} `)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-cli(prod) [1.23s] \033[31m$\033[0m ", cli.renderPrompt(state))
+
+		state.Status, state.Context, state.Elapsed = 0, "", 42*time.Microsecond
+		assert.Equal(t, "test-cli [0s] $ ", cli.renderPrompt(state))
+	})
+
+	t.Run("Invalid Template", func(t *testing.T) {
+		_, err := cli.SetPrompt("{{.Name")
+		assert.Error(t, err)
+	})
+
+	t.Run("Callback", func(t *testing.T) {
+		cli.SetPromptFunc(func(state PromptState) string {
+			return fmt.Sprintf("%s:%d%s ", state.Name, state.Status, state.Symbol)
+		})
+		assert.Equal(t, "test-cli:0$ ", cli.renderPrompt(state))
+	})
+}
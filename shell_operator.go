@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"io"
+	"strings"
+
+	readline "github.com/chzyer/readline"
+	clierrors "github.com/yassirdeveloper/cli/errors"
+	"github.com/yassirdeveloper/cli/operator"
+)
+
+// shellOperator reads the input of the commands run in the interactive shell
+// through its line editor, which would otherwise race them for the standard
+// input. Writes go to the operator of the application.
+type shellOperator struct {
+	operator.Operator
+	line *readline.Instance
+}
+
+func (o *shellOperator) Read() (string, clierrors.Error) {
+	input, err := o.ReadLine("")
+	if err != nil {
+		return "", err
+	}
+	return input + "\n", nil
+}
+
+func (o *shellOperator) ReadLine(prompt string) (string, clierrors.Error) {
+	o.line.SetPrompt(prompt)
+	input, err := o.line.Readline()
+	return strings.TrimSuffix(input, "\n"), o.readError(err)
+}
+
+func (o *shellOperator) ReadPassword(prompt string) (string, clierrors.Error) {
+	password, err := o.line.ReadPassword(prompt)
+	return string(password), o.readError(err)
+}
+
+func (o *shellOperator) IsTerminal() bool {
+	return true
+}
+
+func (o *shellOperator) Width() int {
+	return max(readline.GetScreenWidth(), 0)
+}
+
+// readError reports Ctrl+C as an interruption and Ctrl+D as the end of the
+// input.
+func (o *shellOperator) readError(err error) clierrors.Error {
+	switch err {
+	case nil:
+		return nil
+	case readline.ErrInterrupt:
+		return clierrors.WithExitCode(clierrors.New("Interrupted"), clierrors.ExitInterrupted)
+	case io.EOF:
+		return clierrors.NewUnexpectedError(io.EOF)
+	}
+	return clierrors.NewUnexpectedError(err)
+}